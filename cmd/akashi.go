@@ -32,10 +32,10 @@ var (
 
 func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "akashi <command> <path to ruleset>",
+		Use:          "akashi <command> <path to ruleset>...",
 		Short:        "Akashi / 証",
 		Long:         `Validate "terraform plan" changes against a customizable ruleset`,
-		Args:         cobra.ExactArgs(1),
+		Args:         cobra.MinimumNArgs(1),
 		RunE:         run,
 		SilenceUsage: true,
 	}
@@ -62,31 +62,33 @@ func NewCommand() *cobra.Command {
 }
 
 func run(_ *cobra.Command, args []string) error {
-	comparers, err := compare.NewComparerSet(args[0])
+	comparers, err := compare.NewComparerSet(args...)
 	if err != nil {
 		return err
 	}
 
-	in, err := plan.NewResourcePlans(file, json)
+	in, err := plan.NewPlan(file, json)
 	if err != nil {
 		return err
 	}
 
 	if quiet {
 		fmt.Fprintln(os.Stderr, `[WARN] -q is deprecated. Please run "akashi compare" instead.`)
-		os.Exit(runCompare(in, comparers))
+		os.Exit(runCompare(in.ResourcePlans, in.Drift, comparers))
 	}
 	out := utils.NewOutput(noColor)
 
 	fmt.Fprintln(os.Stderr, `[WARN] no command is deprecated. Please run "akashi diff" instead.`)
-	os.Exit(runDiff(out, in, comparers))
+	os.Exit(runDiff(out, in.ResourcePlans, in.Drift, comparers))
 	return nil
 }
 
-func runCompare(rc []plan.ResourcePlan, comparers compare.ComparerSet) int {
+func runCompare(rc, drift []plan.ResourcePlan, comparers compare.ComparerSet) int {
 	createComparer := comparers.CreateComparer
 	destroyComparer := comparers.DestroyComparer
 	updateComparer := comparers.UpdateComparer
+	replaceComparer := comparers.ReplaceComparer
+	readComparer := comparers.ReadComparer
 
 	for _, r := range rc {
 		if r.IsCreate() && createComparer != nil {
@@ -97,11 +99,34 @@ func runCompare(rc []plan.ResourcePlan, comparers compare.ComparerSet) int {
 			if !destroyComparer.Compare(r) {
 				return 1
 			}
+		} else if r.IsReplace() && replaceComparer != nil {
+			if !replaceComparer.Compare(r) {
+				return 1
+			}
 		} else if r.IsUpdate() && updateComparer != nil {
 			if !updateComparer.Compare(r) {
 				return 1
 			}
-		} else if strict {
+		} else if r.IsRead() && readComparer != nil {
+			if !readComparer.Compare(r) {
+				return 1
+			}
+		} else if strict && compare.RequiresComparer(r) {
+			return 1
+		}
+	}
+
+	if driftComparer := comparers.DriftComparer; driftComparer != nil {
+		for _, r := range drift {
+			if !driftComparer.Compare(r) {
+				return 1
+			}
+		}
+	}
+
+	// Plan comparers run after every resource has been compared
+	for _, c := range comparers.PlanComparers {
+		if !c.Compare(rc) {
 			return 1
 		}
 	}
@@ -109,11 +134,13 @@ func runCompare(rc []plan.ResourcePlan, comparers compare.ComparerSet) int {
 	return 0
 }
 
-func runDiff(out io.Writer, rc []plan.ResourcePlan, comparers compare.ComparerSet) int {
+func runDiff(out io.Writer, rc, drift []plan.ResourcePlan, comparers compare.ComparerSet) int {
 	exitCode := 0
 	createComparer := comparers.CreateComparer
 	destroyComparer := comparers.DestroyComparer
 	updateComparer := comparers.UpdateComparer
+	replaceComparer := comparers.ReplaceComparer
+	readComparer := comparers.ReadComparer
 
 	for _, r := range rc {
 		diff := ""
@@ -122,17 +149,21 @@ func runDiff(out io.Writer, rc []plan.ResourcePlan, comparers compare.ComparerSe
 			diff, pass = createComparer.Diff(r)
 		} else if r.IsDelete() && destroyComparer != nil {
 			diff, pass = destroyComparer.Diff(r)
+		} else if r.IsReplace() && replaceComparer != nil {
+			diff, pass = replaceComparer.Diff(r)
 		} else if r.IsUpdate() && updateComparer != nil {
 			diff, pass = updateComparer.Diff(r)
+		} else if r.IsRead() && readComparer != nil {
+			diff, pass = readComparer.Diff(r)
 		} else {
-			if !strict {
+			if !strict || !compare.RequiresComparer(r) {
 				continue
 			}
 
 			if errorOnFail {
 				exitCode = 1
 			}
-			fmt.Fprintln(out, fmt.Sprintf("%s %s (no matching comparer)", utils.Yellow("?"), r.GetAddress()))
+			fmt.Fprintln(out, fmt.Sprintf("%s %s (%s)", utils.Yellow("?"), r.GetAddress(), compare.DescribeNoComparer(r)))
 			continue
 		}
 		if pass {
@@ -150,5 +181,32 @@ func runDiff(out io.Writer, rc []plan.ResourcePlan, comparers compare.ComparerSe
 		}
 	}
 
+	if driftComparer := comparers.DriftComparer; driftComparer != nil {
+		for _, r := range drift {
+			diff, pass := driftComparer.Diff(r)
+			if pass && failedOnly {
+				continue
+			}
+
+			fmt.Fprintln(out, diff)
+			if !pass && errorOnFail {
+				exitCode = 1
+			}
+		}
+	}
+
+	// Plan comparers run after every resource has been compared
+	for _, c := range comparers.PlanComparers {
+		diff, pass := c.Diff(rc)
+		if pass && (failedOnly || diff == "") {
+			continue
+		}
+
+		fmt.Fprintln(out, diff)
+		if !pass && errorOnFail {
+			exitCode = 1
+		}
+	}
+
 	return exitCode
 }
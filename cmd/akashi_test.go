@@ -104,12 +104,50 @@ func TestRunCompare(t *testing.T) {
 			},
 			expected: 1,
 		},
+		"replace returns false with replace resource": {
+			comparers: compare.ComparerSet{
+				UpdateComparer: &comparefakes.FakeComparer{
+					CompareReturns: true,
+				},
+				ReplaceComparer: &comparefakes.FakeComparer{
+					CompareReturns: false,
+				},
+			},
+			resourceChange: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					ReplaceReturns: true,
+					NameReturns:    "name",
+					TypeReturns:    "type",
+				},
+			},
+			expected: 1,
+		},
+		"plan comparer fails": {
+			comparers: compare.ComparerSet{
+				CreateComparer: &comparefakes.FakeComparer{
+					CompareReturns: true,
+				},
+				PlanComparers: []compare.PlanComparer{
+					&comparefakes.FakePlanComparer{
+						CompareReturns: false,
+					},
+				},
+			},
+			resourceChange: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					CreateReturns: true,
+					NameReturns:   "name",
+					TypeReturns:   "type",
+				},
+			},
+			expected: 1,
+		},
 		// TODO: test case to ensure comparers are called correctly(matching type and number of calls)
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			if got := runCompare(tc.resourceChange, tc.comparers); got != tc.expected {
+			if got := runCompare(tc.resourceChange, nil, tc.comparers); got != tc.expected {
 				t.Errorf("Expected: %v but got %v", tc.expected, got)
 			}
 		})
@@ -198,6 +236,47 @@ func TestRunDiff(t *testing.T) {
 			expected:       0,
 			expectedOutput: []string{"?", "address (no matching comparer)"},
 		},
+		"read resource with strict enabled": {
+			comparers: compare.ComparerSet{
+				CreateComparer: &comparefakes.FakeComparer{
+					DiffReturns: true,
+				},
+			},
+			resourceChange: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					ReadReturns:    true,
+					AddressReturns: "data.type.name",
+					NameReturns:    "name",
+					TypeReturns:    "type",
+				},
+			},
+			preHook: func() {
+				strict = true
+			},
+			expected:       0,
+			expectedOutput: []string{"?", "data.type.name (no rules for read resources)"},
+		},
+		"no-op resource with strict enabled": {
+			comparers: compare.ComparerSet{
+				CreateComparer: &comparefakes.FakeComparer{
+					DiffReturns: true,
+				},
+			},
+			resourceChange: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					NoOpReturns:    true,
+					AddressReturns: "address",
+					NameReturns:    "name",
+					TypeReturns:    "type",
+				},
+			},
+			preHook: func() {
+				strict = true
+				errorOnFail = true
+			},
+			expected:       1,
+			expectedOutput: []string{"?", "address (no matching comparer)"},
+		},
 		"create returns true with multiple resources": {
 			comparers: compare.ComparerSet{
 				CreateComparer: &comparefakes.FakeComparer{
@@ -327,7 +406,7 @@ func TestRunDiff(t *testing.T) {
 			}
 
 			var output bytes.Buffer
-			if got := runDiff(&output, tc.resourceChange, tc.comparers); got != tc.expected {
+			if got := runDiff(&output, tc.resourceChange, nil, tc.comparers); got != tc.expected {
 				t.Errorf("Expected: %v but got %v", tc.expected, got)
 			}
 
@@ -5,6 +5,7 @@ go 1.18
 require (
 	github.com/drlau/tfplanparse v0.0.14
 	github.com/google/go-cmp v0.6.0
+	github.com/hashicorp/go-version v1.6.0
 	github.com/hashicorp/terraform-json v0.21.0
 	github.com/mattn/go-colorable v0.1.13
 	github.com/mgutz/ansi v0.0.0-20200706080929-d51e80ef957d
@@ -14,7 +15,6 @@ require (
 
 require (
 	github.com/apparentlymart/go-textseg/v15 v15.0.0 // indirect
-	github.com/hashicorp/go-version v1.6.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
@@ -11,29 +11,136 @@ type Comparer interface {
 	Diff(plan.ResourcePlan) (string, bool)
 }
 
+// PlanComparer compares the whole plan, after every resource has been compared
+type PlanComparer interface {
+	Compare([]plan.ResourcePlan) bool
+	Diff([]plan.ResourcePlan) (string, bool)
+}
+
 type ComparerSet struct {
 	CreateComparer  Comparer
 	DestroyComparer Comparer
 	UpdateComparer  Comparer
+	ReplaceComparer Comparer
+	ReadComparer    Comparer
+
+	// DriftComparer compares the changes made outside of Terraform, instead of the planned changes
+	DriftComparer Comparer
+
+	PlanComparers []PlanComparer
 }
 
-func NewComparerSet(path string) (ComparerSet, error) {
+func NewComparerSet(paths ...string) (ComparerSet, error) {
 	result := ComparerSet{}
 
-	rs, err := ruleset.ParseRuleset(path)
+	rs, err := ruleset.ParseRuleset(paths...)
 	if err != nil {
 		return result, err
 	}
 
 	if rs.CreatedResources != nil {
-		result.CreateComparer = compare.NewCreateComparer(*rs.CreatedResources)
+		c, err := compare.NewCreateComparer(*rs.CreatedResources)
+		if err != nil {
+			return result, err
+		}
+		result.CreateComparer = c
 	}
 	if rs.DestroyedResources != nil {
-		result.DestroyComparer = compare.NewDestroyComparer(*rs.DestroyedResources)
+		c, err := compare.NewDestroyComparer(*rs.DestroyedResources)
+		if err != nil {
+			return result, err
+		}
+		result.DestroyComparer = c
 	}
 	if rs.UpdatedResources != nil {
-		result.UpdateComparer = compare.NewUpdateComparer(*rs.UpdatedResources)
+		c, err := compare.NewUpdateComparer(*rs.UpdatedResources)
+		if err != nil {
+			return result, err
+		}
+		result.UpdateComparer = c
+	}
+	if rs.ReplacedResources != nil {
+		c, err := compare.NewUpdateComparer(*rs.ReplacedResources)
+		if err != nil {
+			return result, err
+		}
+		result.ReplaceComparer = c
+	} else {
+		// Replaced resources are compared against the updated resources rules if there are no replaced resources rules
+		result.ReplaceComparer = result.UpdateComparer
+	}
+	if rs.ReadResources != nil {
+		c, err := compare.NewCreateComparer(*rs.ReadResources)
+		if err != nil {
+			return result, err
+		}
+		result.ReadComparer = c
+	}
+	if rs.DriftedResources != nil {
+		c, err := compare.NewDriftComparer(*rs.DriftedResources)
+		if err != nil {
+			return result, err
+		}
+		result.DriftComparer = c
+	}
+	if rs.MovedResources != nil {
+		c, err := compare.NewMovedComparer(*rs.MovedResources)
+		if err != nil {
+			return result, err
+		}
+		result.PlanComparers = append(result.PlanComparers, c)
+	}
+	if rs.ImportedResources != nil {
+		c, err := compare.NewImportComparer(*rs.ImportedResources)
+		if err != nil {
+			return result, err
+		}
+		result.PlanComparers = append(result.PlanComparers, c)
+	}
+	if rs.ForgottenResources != nil {
+		c, err := compare.NewForgetComparer(*rs.ForgottenResources)
+		if err != nil {
+			return result, err
+		}
+		result.PlanComparers = append(result.PlanComparers, c)
+	}
+	if len(rs.PlanRules) > 0 {
+		c, err := compare.NewPlanRuleComparer(rs.PlanRules)
+		if err != nil {
+			return result, err
+		}
+		result.PlanComparers = append(result.PlanComparers, c)
+	}
+	if len(rs.MaxCreated) > 0 || len(rs.MaxDestroyed) > 0 || len(rs.MaxReplaced) > 0 {
+		c, err := compare.NewBudgetComparer(rs)
+		if err != nil {
+			return result, err
+		}
+		result.PlanComparers = append(result.PlanComparers, c)
 	}
 
 	return result, nil
 }
+
+// RequiresComparer returns true if strict mode requires the resource to match a comparer
+// Forgotten resources are only compared by the forgottenResources rules
+func RequiresComparer(r plan.ResourcePlan) bool {
+	return !r.IsForget()
+}
+
+// DescribeNoComparer describes the planned action of a resource without a matching comparer
+func DescribeNoComparer(r plan.ResourcePlan) string {
+	switch {
+	case r.IsCreate():
+		return "no rules for created resources"
+	case r.IsDelete():
+		return "no rules for destroyed resources"
+	case r.IsReplace():
+		return "no rules for replaced resources"
+	case r.IsUpdate():
+		return "no rules for updated resources"
+	case r.IsRead():
+		return "no rules for read resources"
+	}
+	return "no matching comparer"
+}
@@ -17,3 +17,17 @@ func (r *FakeComparer) Compare(rc plan.ResourcePlan) bool {
 func (r *FakeComparer) Diff(rc plan.ResourcePlan) (string, bool) {
 	return r.DiffOutput, r.DiffReturns
 }
+
+type FakePlanComparer struct {
+	CompareReturns bool
+	DiffReturns    bool
+	DiffOutput     string
+}
+
+func (r *FakePlanComparer) Compare(rc []plan.ResourcePlan) bool {
+	return r.CompareReturns
+}
+
+func (r *FakePlanComparer) Diff(rc []plan.ResourcePlan) (string, bool) {
+	return r.DiffOutput, r.DiffReturns
+}
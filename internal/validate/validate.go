@@ -2,9 +2,14 @@ package validate
 
 import (
 	"fmt"
+	"net/netip"
+	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/drlau/akashi/pkg/ruleset"
+	"github.com/hashicorp/go-version"
 )
 
 // TODO: currently the only static validation we do is to check if names are
@@ -15,6 +20,12 @@ type ValidateResult struct {
 	InvalidCreatedResources   []*ruleset.ResourceIdentifier
 	InvalidDestroyedResources []*ruleset.ResourceIdentifier
 	InvalidUpdatedResources   []*ruleset.ResourceIdentifier
+	InvalidReplacedResources  []*ruleset.ResourceIdentifier
+	InvalidReadResources      []*ruleset.ResourceIdentifier
+	InvalidDriftedResources   []*ruleset.ResourceIdentifier
+
+	// InvalidRules describes rules that can not be evaluated, such as invalid regular expressions
+	InvalidRules []string
 }
 
 func (r *ValidateResult) fill_defaults() {
@@ -27,6 +38,18 @@ func (r *ValidateResult) fill_defaults() {
 	if r.InvalidUpdatedResources == nil {
 		r.InvalidUpdatedResources = make([]*ruleset.ResourceIdentifier, 0)
 	}
+	if r.InvalidReplacedResources == nil {
+		r.InvalidReplacedResources = make([]*ruleset.ResourceIdentifier, 0)
+	}
+	if r.InvalidReadResources == nil {
+		r.InvalidReadResources = make([]*ruleset.ResourceIdentifier, 0)
+	}
+	if r.InvalidDriftedResources == nil {
+		r.InvalidDriftedResources = make([]*ruleset.ResourceIdentifier, 0)
+	}
+	if r.InvalidRules == nil {
+		r.InvalidRules = make([]string, 0)
+	}
 }
 
 func formatResourceIDs(ids []*ruleset.ResourceIdentifier) []string {
@@ -58,6 +81,24 @@ func (r *ValidateResult) String() string {
 		lines = append(lines, "Invalid Updated Resources:")
 		lines = append(lines, formatResourceIDs(r.InvalidUpdatedResources)...)
 	}
+	if len(r.InvalidReplacedResources) != 0 {
+		lines = append(lines, "Invalid Replaced Resources:")
+		lines = append(lines, formatResourceIDs(r.InvalidReplacedResources)...)
+	}
+	if len(r.InvalidReadResources) != 0 {
+		lines = append(lines, "Invalid Read Resources:")
+		lines = append(lines, formatResourceIDs(r.InvalidReadResources)...)
+	}
+	if len(r.InvalidDriftedResources) != 0 {
+		lines = append(lines, "Invalid Drifted Resources:")
+		lines = append(lines, formatResourceIDs(r.InvalidDriftedResources)...)
+	}
+	if len(r.InvalidRules) != 0 {
+		lines = append(lines, "Invalid Rules:")
+		for _, rule := range r.InvalidRules {
+			lines = append(lines, fmt.Sprintf("\t- %s", rule))
+		}
+	}
 	return strings.Join(lines, "\n")
 }
 
@@ -65,7 +106,11 @@ func (r *ValidateResult) IsValid() bool {
 	createdValid := len(r.InvalidCreatedResources) == 0
 	destroyedValid := len(r.InvalidDestroyedResources) == 0
 	updatedValid := len(r.InvalidUpdatedResources) == 0
-	return createdValid && destroyedValid && updatedValid
+	replacedValid := len(r.InvalidReplacedResources) == 0
+	readValid := len(r.InvalidReadResources) == 0
+	driftedValid := len(r.InvalidDriftedResources) == 0
+	rulesValid := len(r.InvalidRules) == 0
+	return createdValid && destroyedValid && updatedValid && replacedValid && readValid && driftedValid && rulesValid
 }
 
 func getUnnamedResources[T ruleset.Resource](rs []T) []*ruleset.ResourceIdentifier {
@@ -79,6 +124,262 @@ func getUnnamedResources[T ruleset.Resource](rs []T) []*ruleset.ResourceIdentifi
 	return res
 }
 
+func getInvalidRules[T ruleset.Resource](section string, rs []T) []string {
+	var res []string
+	for _, r := range rs {
+		id := r.ID()
+		if id.IsAddressPattern() {
+			if _, err := id.AddressPattern(); err != nil {
+				res = append(res, fmt.Sprintf("%s: %s: invalid address pattern: %v", section, id.String(), err))
+			}
+		}
+		for _, rules := range r.Rules() {
+			for _, invalid := range getInvalidEnforced("", rules.Enforced) {
+				res = append(res, fmt.Sprintf("%s: %s: %s", section, id.String(), invalid))
+			}
+		}
+	}
+	return res
+}
+
+func getInvalidEnforced(keyPrefix string, enforced map[string]ruleset.EnforceChange) []string {
+	var keys []string
+	for k := range enforced {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var res []string
+	for _, k := range keys {
+		key := k
+		if keyPrefix != "" {
+			key = fmt.Sprintf("%s.%s", keyPrefix, k)
+		}
+		res = append(res, getInvalidEnforceChange(key, enforced[k])...)
+	}
+	return res
+}
+
+func getInvalidEnforceChange(key string, e ruleset.EnforceChange) []string {
+	var res []string
+	if _, err := regexp.Compile(e.MatchRegex); err != nil {
+		res = append(res, fmt.Sprintf("%s: invalid matchRegex: %v", key, err))
+	}
+	if _, err := regexp.Compile(e.NotMatchRegex); err != nil {
+		res = append(res, fmt.Sprintf("%s: invalid notMatchRegex: %v", key, err))
+	}
+	res = append(res, getInvalidCIDRs(fmt.Sprintf("%s: invalid withinCIDR", key), e.WithinCIDR)...)
+	res = append(res, getInvalidCIDRs(fmt.Sprintf("%s: invalid notOverlapCIDR", key), e.NotOverlapCIDR)...)
+	if e.VersionConstraint != "" {
+		if _, err := version.NewConstraint(e.VersionConstraint); err != nil {
+			res = append(res, fmt.Sprintf("%s: invalid versionConstraint: %v", key, err))
+		}
+	}
+	if e.Min != nil && e.Max != nil && *e.Min > *e.Max {
+		res = append(res, fmt.Sprintf("%s: min %v is greater than max %v", key, *e.Min, *e.Max))
+	}
+	if e.GreaterThan != nil && e.LessThan != nil && *e.GreaterThan >= *e.LessThan {
+		res = append(res, fmt.Sprintf("%s: greaterThan %v is not less than lessThan %v", key, *e.GreaterThan, *e.LessThan))
+	}
+
+	if e.Not != nil {
+		res = append(res, getInvalidBranch(fmt.Sprintf("%s.not", key), *e.Not)...)
+	}
+	res = append(res, getInvalidEnforceChanges(fmt.Sprintf("%s.allOf", key), e.AllOf)...)
+	res = append(res, getInvalidEnforceChanges(fmt.Sprintf("%s.anyOf", key), e.AnyOf)...)
+	res = append(res, getInvalidEnforceChanges(fmt.Sprintf("%s.oneOf", key), e.OneOf)...)
+	if e.JSON != nil {
+		res = append(res, getInvalidEnforceChange(fmt.Sprintf("%s.json", key), *e.JSON)...)
+	}
+	if e.Each != nil {
+		res = append(res, getInvalidEnforceChange(fmt.Sprintf("%s.each", key), *e.Each)...)
+	}
+	if e.Any != nil {
+		res = append(res, getInvalidEnforceChange(fmt.Sprintf("%s.any", key), *e.Any)...)
+	}
+	if e.None != nil {
+		res = append(res, getInvalidEnforceChange(fmt.Sprintf("%s.none", key), *e.None)...)
+	}
+
+	return append(res, getInvalidEnforced(key, e.EnforceChange)...)
+}
+
+func getInvalidCIDRs(prefix string, cidrs []string) []string {
+	var res []string
+	for _, cidr := range cidrs {
+		if _, err := netip.ParsePrefix(cidr); err != nil {
+			res = append(res, fmt.Sprintf("%s: %v", prefix, err))
+		}
+	}
+	return res
+}
+
+func getInvalidEnforceChanges(key string, es []ruleset.EnforceChange) []string {
+	if es != nil && len(es) == 0 {
+		return []string{fmt.Sprintf("%s: empty list of rules", key)}
+	}
+
+	var res []string
+	for i, e := range es {
+		res = append(res, getInvalidBranch(fmt.Sprintf("%s[%d]", key, i), e)...)
+	}
+	return res
+}
+
+// getInvalidBranch returns the invalid rules of a branch of not, allOf, anyOf or oneOf
+// A branch without rules matches every value, so it is always invalid
+func getInvalidBranch(key string, e ruleset.EnforceChange) []string {
+	if isEmptyEnforceChange(e) {
+		return []string{fmt.Sprintf("%s: empty rule", key)}
+	}
+	return getInvalidEnforceChange(key, e)
+}
+
+// isEmptyEnforceChange returns true if the EnforceChange sets no matcher and no nested rule
+func isEmptyEnforceChange(e ruleset.EnforceChange) bool {
+	if len(e.EnforceChange) > 0 {
+		return false
+	}
+	e.EnforceChange = nil
+	return reflect.DeepEqual(e, ruleset.EnforceChange{})
+}
+
+// getMisplacedRelationalRules returns the rules using relational matchers outside the after rules of updated and replaced resources
+// Relational matchers compare a value to its value before the change, which only exists for updated and replaced resources
+func getMisplacedRelationalRules(section string, id *ruleset.ResourceIdentifier, rules *ruleset.ResourceRules) []string {
+	if rules == nil {
+		return nil
+	}
+
+	var res []string
+	for _, key := range getRelationalKeys("", rules.Enforced) {
+		res = append(res, fmt.Sprintf("%s: %s: %s: relational matchers are only supported in after rules of updated, replaced and drifted resources", section, id.String(), key))
+	}
+	return res
+}
+
+func getRelationalKeys(keyPrefix string, enforced map[string]ruleset.EnforceChange) []string {
+	var keys []string
+	for k := range enforced {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var res []string
+	for _, k := range keys {
+		key := k
+		if keyPrefix != "" {
+			key = fmt.Sprintf("%s.%s", keyPrefix, k)
+		}
+		res = append(res, getRelationalKeysForChange(key, enforced[k])...)
+	}
+	return res
+}
+
+func getRelationalKeysForChange(key string, e ruleset.EnforceChange) []string {
+	var res []string
+	if e.IncreaseOnly || e.DecreaseOnly || e.Unchanged || e.MaxDelta != nil || e.MaxPercentChange != nil {
+		res = append(res, key)
+	}
+	if e.Not != nil {
+		res = append(res, getRelationalKeysForChange(fmt.Sprintf("%s.not", key), *e.Not)...)
+	}
+	res = append(res, getRelationalKeysForChanges(fmt.Sprintf("%s.allOf", key), e.AllOf)...)
+	res = append(res, getRelationalKeysForChanges(fmt.Sprintf("%s.anyOf", key), e.AnyOf)...)
+	res = append(res, getRelationalKeysForChanges(fmt.Sprintf("%s.oneOf", key), e.OneOf)...)
+	if e.JSON != nil {
+		res = append(res, getRelationalKeysForChange(fmt.Sprintf("%s.json", key), *e.JSON)...)
+	}
+	if e.Each != nil {
+		res = append(res, getRelationalKeysForChange(fmt.Sprintf("%s.each", key), *e.Each)...)
+	}
+	if e.Any != nil {
+		res = append(res, getRelationalKeysForChange(fmt.Sprintf("%s.any", key), *e.Any)...)
+	}
+	if e.None != nil {
+		res = append(res, getRelationalKeysForChange(fmt.Sprintf("%s.none", key), *e.None)...)
+	}
+	return append(res, getRelationalKeys(key, e.EnforceChange)...)
+}
+
+func getRelationalKeysForChanges(key string, es []ruleset.EnforceChange) []string {
+	var res []string
+	for i, e := range es {
+		res = append(res, getRelationalKeysForChange(fmt.Sprintf("%s[%d]", key, i), e)...)
+	}
+	return res
+}
+
+func getInvalidPlanRules(rules []ruleset.PlanRule) []string {
+	var res []string
+	for _, r := range rules {
+		for _, invalid := range getInvalidPlanRuleSelector("resource", r.Resource) {
+			res = append(res, fmt.Sprintf("planRules: %s: %s", r.String(), invalid))
+		}
+		for _, invalid := range getInvalidPlanRuleSelector("requires", r.Requires) {
+			res = append(res, fmt.Sprintf("planRules: %s: %s", r.String(), invalid))
+		}
+	}
+	return res
+}
+
+func getInvalidPlanRuleSelector(key string, s ruleset.PlanRuleSelector) []string {
+	var res []string
+	if s.IsAddressPattern() {
+		if _, err := s.AddressPattern(); err != nil {
+			res = append(res, fmt.Sprintf("%s: invalid address pattern: %v", key, err))
+		}
+	}
+	for _, action := range s.Actions {
+		switch action {
+		case ruleset.ActionCreate, ruleset.ActionDestroy, ruleset.ActionUpdate, ruleset.ActionReplace, ruleset.ActionRead:
+		default:
+			res = append(res, fmt.Sprintf("%s: unknown action %q", key, action))
+		}
+	}
+	return res
+}
+
+// getInvalidOperationRules returns the invalid rules of moved, imported and forgotten resources
+// Only moved resources have a previous address to check from against
+func getInvalidOperationRules(section string, rules *ruleset.OperationChanges) []string {
+	if rules == nil {
+		return nil
+	}
+
+	res := getInvalidRules(section, rules.Resources)
+	if section == "movedResources" {
+		return res
+	}
+	for _, r := range rules.Resources {
+		if len(r.From) > 0 {
+			res = append(res, fmt.Sprintf("%s: %s: from is only supported in movedResources", section, r.ID().String()))
+		}
+	}
+	return res
+}
+
+func getInvalidMatchMode(section, matchMode string) []string {
+	switch matchMode {
+	case "", ruleset.MatchModeFirst, ruleset.MatchModeAll:
+		return nil
+	}
+	return []string{fmt.Sprintf("%s: unknown matchMode %q, must be %q or %q", section, matchMode, ruleset.MatchModeFirst, ruleset.MatchModeAll)}
+}
+
+func getInvalidBudgets(section string, budgets ruleset.Budgets) []string {
+	var res []string
+	for _, b := range budgets {
+		switch {
+		case b.Count == nil:
+			res = append(res, fmt.Sprintf("%s: %s: missing count", section, b.String()))
+		case *b.Count < 0:
+			res = append(res, fmt.Sprintf("%s: %s: negative count %d", section, b.String(), *b.Count))
+		}
+	}
+	return res
+}
+
 func Validate(rs ruleset.Ruleset) *ValidateResult {
 	res := &ValidateResult{}
 	if rs.CreatedResources != nil && rs.CreatedResources.RequireName {
@@ -93,5 +394,66 @@ func Validate(rs ruleset.Ruleset) *ValidateResult {
 		ids := getUnnamedResources(rs.UpdatedResources.Resources)
 		res.InvalidUpdatedResources = ids
 	}
+	if rs.ReplacedResources != nil && rs.ReplacedResources.RequireName {
+		ids := getUnnamedResources(rs.ReplacedResources.Resources)
+		res.InvalidReplacedResources = ids
+	}
+	if rs.ReadResources != nil && rs.ReadResources.RequireName {
+		ids := getUnnamedResources(rs.ReadResources.Resources)
+		res.InvalidReadResources = ids
+	}
+	if rs.DriftedResources != nil && rs.DriftedResources.RequireName {
+		ids := getUnnamedResources(rs.DriftedResources.Resources)
+		res.InvalidDriftedResources = ids
+	}
+	if rs.CreatedResources != nil {
+		res.InvalidRules = append(res.InvalidRules, getInvalidMatchMode("createdResources", rs.CreatedResources.MatchMode)...)
+		res.InvalidRules = append(res.InvalidRules, getInvalidRules("createdResources", rs.CreatedResources.Resources)...)
+		for _, r := range rs.CreatedResources.Resources {
+			res.InvalidRules = append(res.InvalidRules, getMisplacedRelationalRules("createdResources", r.ID(), &r.ResourceRules)...)
+		}
+	}
+	if rs.DestroyedResources != nil {
+		res.InvalidRules = append(res.InvalidRules, getInvalidMatchMode("destroyedResources", rs.DestroyedResources.MatchMode)...)
+		res.InvalidRules = append(res.InvalidRules, getInvalidRules("destroyedResources", rs.DestroyedResources.Resources)...)
+		for _, r := range rs.DestroyedResources.Resources {
+			res.InvalidRules = append(res.InvalidRules, getMisplacedRelationalRules("destroyedResources", r.ID(), &r.ResourceRules)...)
+		}
+	}
+	if rs.UpdatedResources != nil {
+		res.InvalidRules = append(res.InvalidRules, getInvalidMatchMode("updatedResources", rs.UpdatedResources.MatchMode)...)
+		res.InvalidRules = append(res.InvalidRules, getInvalidRules("updatedResources", rs.UpdatedResources.Resources)...)
+		for _, r := range rs.UpdatedResources.Resources {
+			res.InvalidRules = append(res.InvalidRules, getMisplacedRelationalRules("updatedResources", r.ID(), r.Before)...)
+		}
+	}
+	if rs.ReplacedResources != nil {
+		res.InvalidRules = append(res.InvalidRules, getInvalidMatchMode("replacedResources", rs.ReplacedResources.MatchMode)...)
+		res.InvalidRules = append(res.InvalidRules, getInvalidRules("replacedResources", rs.ReplacedResources.Resources)...)
+		for _, r := range rs.ReplacedResources.Resources {
+			res.InvalidRules = append(res.InvalidRules, getMisplacedRelationalRules("replacedResources", r.ID(), r.Before)...)
+		}
+	}
+	if rs.ReadResources != nil {
+		res.InvalidRules = append(res.InvalidRules, getInvalidMatchMode("readResources", rs.ReadResources.MatchMode)...)
+		res.InvalidRules = append(res.InvalidRules, getInvalidRules("readResources", rs.ReadResources.Resources)...)
+		for _, r := range rs.ReadResources.Resources {
+			res.InvalidRules = append(res.InvalidRules, getMisplacedRelationalRules("readResources", r.ID(), &r.ResourceRules)...)
+		}
+	}
+	if rs.DriftedResources != nil {
+		res.InvalidRules = append(res.InvalidRules, getInvalidMatchMode("driftedResources", rs.DriftedResources.MatchMode)...)
+		res.InvalidRules = append(res.InvalidRules, getInvalidRules("driftedResources", rs.DriftedResources.Resources)...)
+		for _, r := range rs.DriftedResources.Resources {
+			res.InvalidRules = append(res.InvalidRules, getMisplacedRelationalRules("driftedResources", r.ID(), r.Before)...)
+		}
+	}
+	res.InvalidRules = append(res.InvalidRules, getInvalidOperationRules("movedResources", rs.MovedResources)...)
+	res.InvalidRules = append(res.InvalidRules, getInvalidOperationRules("importedResources", rs.ImportedResources)...)
+	res.InvalidRules = append(res.InvalidRules, getInvalidOperationRules("forgottenResources", rs.ForgottenResources)...)
+	res.InvalidRules = append(res.InvalidRules, getInvalidPlanRules(rs.PlanRules)...)
+	res.InvalidRules = append(res.InvalidRules, getInvalidBudgets("maxCreated", rs.MaxCreated)...)
+	res.InvalidRules = append(res.InvalidRules, getInvalidBudgets("maxDestroyed", rs.MaxDestroyed)...)
+	res.InvalidRules = append(res.InvalidRules, getInvalidBudgets("maxReplaced", rs.MaxReplaced)...)
 	return res
 }
@@ -77,6 +77,290 @@ func TestValidate(t *testing.T) {
 				},
 			},
 		},
+		"invalid regex": {
+			rs: ruleset.Ruleset{
+				UpdatedResources: &ruleset.UpdateResourceChanges{
+					Resources: []ruleset.UpdateResourceChange{
+						{
+							ResourceIdentifier: ruleset.ResourceIdentifier{
+								AddressRegex: "module\\.(",
+							},
+						},
+						{
+							ResourceIdentifier: ruleset.ResourceIdentifier{
+								Type: "google_storage_bucket",
+							},
+							After: &ruleset.ResourceRules{
+								Enforced: map[string]ruleset.EnforceChange{
+									"name": {
+										MatchRegex: "^prod-[a-z",
+									},
+									"labels": {
+										EnforceChange: map[string]ruleset.EnforceChange{
+											"env": {
+												NotMatchRegex: "(dev",
+											},
+										},
+									},
+									"location": {
+										AnyOf: []ruleset.EnforceChange{
+											{MatchRegex: "^us-"},
+											{Not: &ruleset.EnforceChange{MatchRegex: "^eu-("}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: &ValidateResult{
+				InvalidRules: []string{
+					"updatedResources: /module\\.(/: invalid address pattern: error parsing regexp: missing closing ): `module\\.(`",
+					"updatedResources: google_storage_bucket: labels.env: invalid notMatchRegex: error parsing regexp: missing closing ): `(dev`",
+					"updatedResources: google_storage_bucket: location.anyOf[1].not: invalid matchRegex: error parsing regexp: missing closing ): `^eu-(`",
+					"updatedResources: google_storage_bucket: name: invalid matchRegex: error parsing regexp: missing closing ]: `[a-z`",
+				},
+			},
+		},
+		"relational matchers outside after rules": {
+			rs: ruleset.Ruleset{
+				CreatedResources: &ruleset.CreateDeleteResourceChanges{
+					Resources: []ruleset.CreateDeleteResourceChange{
+						{
+							ResourceIdentifier: ruleset.ResourceIdentifier{
+								Type: "google_compute_disk",
+							},
+							ResourceRules: ruleset.ResourceRules{
+								Enforced: map[string]ruleset.EnforceChange{
+									"size": {
+										IncreaseOnly: true,
+									},
+									"labels": {
+										JSON: &ruleset.EnforceChange{
+											Not: &ruleset.EnforceChange{
+												Unchanged: true,
+											},
+										},
+									},
+									"disks": {
+										Any: &ruleset.EnforceChange{
+											AnyOf: []ruleset.EnforceChange{
+												{HasPrefix: "pd-"},
+												{DecreaseOnly: true},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				UpdatedResources: &ruleset.UpdateResourceChanges{
+					Resources: []ruleset.UpdateResourceChange{
+						{
+							ResourceIdentifier: ruleset.ResourceIdentifier{
+								Type: "google_container_node_pool",
+							},
+							Before: &ruleset.ResourceRules{
+								Enforced: map[string]ruleset.EnforceChange{
+									"autoscaling": {
+										EnforceChange: map[string]ruleset.EnforceChange{
+											"max_node_count": {
+												MaxDelta: floatPointer(2),
+											},
+										},
+									},
+								},
+							},
+							After: &ruleset.ResourceRules{
+								Enforced: map[string]ruleset.EnforceChange{
+									"node_count": {
+										MaxDelta: floatPointer(2),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: &ValidateResult{
+				InvalidRules: []string{
+					"createdResources: google_compute_disk: disks.any.anyOf[1]: relational matchers are only supported in after rules of updated, replaced and drifted resources",
+					"createdResources: google_compute_disk: labels.json.not: relational matchers are only supported in after rules of updated, replaced and drifted resources",
+					"createdResources: google_compute_disk: size: relational matchers are only supported in after rules of updated, replaced and drifted resources",
+					"updatedResources: google_container_node_pool: autoscaling.max_node_count: relational matchers are only supported in after rules of updated, replaced and drifted resources",
+				},
+			},
+		},
+		"invalid plan rules": {
+			rs: ruleset.Ruleset{
+				PlanRules: []ruleset.PlanRule{
+					{
+						Name: "buckets have public access blocks",
+						Resource: ruleset.PlanRuleSelector{
+							ResourceIdentifier: ruleset.ResourceIdentifier{
+								Type: "aws_s3_bucket",
+							},
+							Actions: []string{"created"},
+						},
+						Requires: ruleset.PlanRuleSelector{
+							ResourceIdentifier: ruleset.ResourceIdentifier{
+								AddressRegex: "aws_s3_bucket_public_access_block\\.(",
+							},
+						},
+					},
+				},
+			},
+			expected: &ValidateResult{
+				InvalidRules: []string{
+					"planRules: buckets have public access blocks: resource: unknown action \"created\"",
+					"planRules: buckets have public access blocks: requires: invalid address pattern: error parsing regexp: missing closing ): `aws_s3_bucket_public_access_block\\.(`",
+				},
+			},
+		},
+		"invalid operation rules": {
+			rs: ruleset.Ruleset{
+				MovedResources: &ruleset.OperationChanges{
+					Resources: []ruleset.OperationChange{
+						{
+							ResourceIdentifier: ruleset.ResourceIdentifier{
+								AddressRegex: "module\\.(",
+							},
+							From: []string{"module.old.**"},
+						},
+					},
+				},
+				ForgottenResources: &ruleset.OperationChanges{
+					Resources: []ruleset.OperationChange{
+						{
+							ResourceIdentifier: ruleset.ResourceIdentifier{
+								Type: "aws_db_instance",
+							},
+							From: []string{"module.old.**"},
+						},
+					},
+				},
+			},
+			expected: &ValidateResult{
+				InvalidRules: []string{
+					"movedResources: /module\\.(/: invalid address pattern: error parsing regexp: missing closing ): `module\\.(`",
+					"forgottenResources: aws_db_instance: from is only supported in movedResources",
+				},
+			},
+		},
+		"empty combinator branches": {
+			rs: ruleset.Ruleset{
+				CreatedResources: &ruleset.CreateDeleteResourceChanges{
+					Resources: []ruleset.CreateDeleteResourceChange{
+						{
+							ResourceIdentifier: ruleset.ResourceIdentifier{
+								Type: "aws_s3_bucket",
+							},
+							ResourceRules: ruleset.ResourceRules{
+								Enforced: map[string]ruleset.EnforceChange{
+									"acl": {
+										Not: &ruleset.EnforceChange{
+											EnforceChange: map[string]ruleset.EnforceChange{},
+										},
+									},
+									"bucket": {
+										AnyOf: []ruleset.EnforceChange{
+											{HasPrefix: "logs-"},
+											{},
+										},
+										AllOf: []ruleset.EnforceChange{},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: &ValidateResult{
+				InvalidRules: []string{
+					"createdResources: aws_s3_bucket: acl.not: empty rule",
+					"createdResources: aws_s3_bucket: bucket.allOf: empty list of rules",
+					"createdResources: aws_s3_bucket: bucket.anyOf[1]: empty rule",
+				},
+			},
+		},
+		"invalid match mode": {
+			rs: ruleset.Ruleset{
+				CreatedResources: &ruleset.CreateDeleteResourceChanges{
+					MatchMode: "al",
+				},
+				UpdatedResources: &ruleset.UpdateResourceChanges{
+					MatchMode: ruleset.MatchModeAll,
+				},
+			},
+			expected: &ValidateResult{
+				InvalidRules: []string{
+					`createdResources: unknown matchMode "al", must be "first" or "all"`,
+				},
+			},
+		},
+		"invalid budgets": {
+			rs: ruleset.Ruleset{
+				MaxDestroyed: ruleset.Budgets{
+					{Type: "aws_db_instance"},
+				},
+				MaxReplaced: ruleset.Budgets{
+					{Count: intPointer(-1)},
+				},
+			},
+			expected: &ValidateResult{
+				InvalidRules: []string{
+					"maxDestroyed: aws_db_instance: missing count",
+					"maxReplaced: all resources: negative count -1",
+				},
+			},
+		},
+		"invalid number range": {
+			rs: ruleset.Ruleset{
+				CreatedResources: &ruleset.CreateDeleteResourceChanges{
+					Resources: []ruleset.CreateDeleteResourceChange{
+						{
+							ResourceIdentifier: ruleset.ResourceIdentifier{
+								Type: "google_container_node_pool",
+							},
+							ResourceRules: ruleset.ResourceRules{
+								Enforced: map[string]ruleset.EnforceChange{
+									"node_count": {
+										Min: floatPointer(50),
+										Max: floatPointer(1),
+									},
+									"version": {
+										VersionConstraint: ">= one.two",
+									},
+									"source_ranges": {
+										WithinCIDR: []string{"10.0.0.0/8", "10.0.0.0/33"},
+									},
+									"ingress": {
+										Each: &ruleset.EnforceChange{
+											EnforceChange: map[string]ruleset.EnforceChange{
+												"from_port": {
+													GreaterThan: floatPointer(443),
+													LessThan:    floatPointer(80),
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: &ValidateResult{
+				InvalidRules: []string{
+					"createdResources: google_container_node_pool: ingress.each.from_port: greaterThan 443 is not less than lessThan 80",
+					"createdResources: google_container_node_pool: node_count: min 50 is greater than max 1",
+					"createdResources: google_container_node_pool: source_ranges: invalid withinCIDR: netip.ParsePrefix(\"10.0.0.0/33\"): prefix length out of range",
+					"createdResources: google_container_node_pool: version: invalid versionConstraint: Malformed constraint: >= one.two",
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -122,6 +406,36 @@ func TestIsValid(t *testing.T) {
 			},
 			expected: false,
 		},
+		"invalid replaced resources": {
+			res: ValidateResult{
+				InvalidReplacedResources: []*ruleset.ResourceIdentifier{
+					{Type: "fake_replace_resource"},
+				},
+			},
+			expected: false,
+		},
+		"invalid read resources": {
+			res: ValidateResult{
+				InvalidReadResources: []*ruleset.ResourceIdentifier{
+					{Type: "fake_read_resource"},
+				},
+			},
+			expected: false,
+		},
+		"invalid drifted resources": {
+			res: ValidateResult{
+				InvalidDriftedResources: []*ruleset.ResourceIdentifier{
+					{Type: "fake_drift_resource"},
+				},
+			},
+			expected: false,
+		},
+		"invalid rules": {
+			res: ValidateResult{
+				InvalidRules: []string{"createdResources: type: key: invalid matchRegex"},
+			},
+			expected: false,
+		},
 		"multiple invalid resources changes": {
 			res: ValidateResult{
 				InvalidCreatedResources: []*ruleset.ResourceIdentifier{
@@ -143,3 +457,11 @@ func TestIsValid(t *testing.T) {
 		})
 	}
 }
+
+func floatPointer(f float64) *float64 {
+	return &f
+}
+
+func intPointer(i int) *int {
+	return &i
+}
@@ -18,23 +18,23 @@ type CompareOptions struct {
 func NewCmdCompare() *cobra.Command {
 	opts := &CompareOptions{}
 	cmd := &cobra.Command{
-		Use:   "compare <path to ruleset>",
+		Use:   "compare <path to ruleset>...",
 		Short: "Validate silently",
 		Long:  `Validate "terraform plan" changes against a ruleset, exiting with code 0 if ok`,
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			comparers, err := compare.NewComparerSet(args[0])
+			comparers, err := compare.NewComparerSet(args...)
 			if err != nil {
 				return err
 			}
 
-			plans, err := plan.NewResourcePlans(opts.File, opts.JSON)
+			p, err := plan.NewPlan(opts.File, opts.JSON)
 			if err != nil {
 				return err
 			}
 
 			cmd.SilenceErrors = true
-			if result := runCompare(plans, comparers, opts.Strict); result != 0 {
+			if result := runCompare(p.ResourcePlans, p.Drift, comparers, opts.Strict); result != 0 {
 				return fmt.Errorf("compare failed")
 			}
 
@@ -49,10 +49,13 @@ func NewCmdCompare() *cobra.Command {
 	return cmd
 }
 
-func runCompare(rc []plan.ResourcePlan, comparers compare.ComparerSet, strict bool) int {
+// runCompare compares the planned changes, and the changes made outside of Terraform against the drifted resources rules
+func runCompare(rc, drift []plan.ResourcePlan, comparers compare.ComparerSet, strict bool) int {
 	createComparer := comparers.CreateComparer
 	destroyComparer := comparers.DestroyComparer
 	updateComparer := comparers.UpdateComparer
+	replaceComparer := comparers.ReplaceComparer
+	readComparer := comparers.ReadComparer
 
 	for _, r := range rc {
 		if r.IsCreate() && createComparer != nil {
@@ -63,11 +66,34 @@ func runCompare(rc []plan.ResourcePlan, comparers compare.ComparerSet, strict bo
 			if !destroyComparer.Compare(r) {
 				return 1
 			}
+		} else if r.IsReplace() && replaceComparer != nil {
+			if !replaceComparer.Compare(r) {
+				return 1
+			}
 		} else if r.IsUpdate() && updateComparer != nil {
 			if !updateComparer.Compare(r) {
 				return 1
 			}
-		} else if strict {
+		} else if r.IsRead() && readComparer != nil {
+			if !readComparer.Compare(r) {
+				return 1
+			}
+		} else if strict && compare.RequiresComparer(r) {
+			return 1
+		}
+	}
+
+	if driftComparer := comparers.DriftComparer; driftComparer != nil {
+		for _, r := range drift {
+			if !driftComparer.Compare(r) {
+				return 1
+			}
+		}
+	}
+
+	// Plan comparers run after every resource has been compared
+	for _, c := range comparers.PlanComparers {
+		if !c.Compare(rc) {
 			return 1
 		}
 	}
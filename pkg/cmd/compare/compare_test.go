@@ -30,6 +30,26 @@ func TestRunCompare(t *testing.T) {
 			},
 			expected: 1,
 		},
+		"plan comparer returns false": {
+			comparers: compare.ComparerSet{
+				CreateComparer: &comparefakes.FakeComparer{
+					CompareReturns: true,
+				},
+				PlanComparers: []compare.PlanComparer{
+					&comparefakes.FakePlanComparer{
+						CompareReturns: false,
+					},
+				},
+			},
+			resourcePlan: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					CreateReturns: true,
+					NameReturns:   "name",
+					TypeReturns:   "type",
+				},
+			},
+			expected: 1,
+		},
 		"create returns true with create resource": {
 			comparers: compare.ComparerSet{
 				CreateComparer: &comparefakes.FakeComparer{
@@ -107,7 +127,7 @@ func TestRunCompare(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			if got := runCompare(tc.resourcePlan, tc.comparers, false); got != tc.expected {
+			if got := runCompare(tc.resourcePlan, nil, tc.comparers, false); got != tc.expected {
 				t.Errorf("Expected: %v but got %v", tc.expected, got)
 			}
 		})
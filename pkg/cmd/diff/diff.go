@@ -23,24 +23,24 @@ type DiffOptions struct {
 func NewCmdDiff() *cobra.Command {
 	opts := &DiffOptions{}
 	cmd := &cobra.Command{
-		Use:   "diff <path to ruleset>",
+		Use:   "diff <path to ruleset>...",
 		Short: "Validate changes",
 		Long:  `Validate "terraform plan" changes against a ruleset`,
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			comparers, err := compare.NewComparerSet(args[0])
+			comparers, err := compare.NewComparerSet(args...)
 			if err != nil {
 				return err
 			}
 
-			plan, err := plan.NewResourcePlans(opts.File, opts.JSON)
+			p, err := plan.NewPlan(opts.File, opts.JSON)
 			if err != nil {
 				return err
 			}
 
 			out := utils.NewOutput(opts.NoColor)
 			cmd.SilenceErrors = true
-			if result := runDiff(out, plan, comparers, opts); result != 0 {
+			if result := runDiff(out, p.ResourcePlans, p.Drift, comparers, opts); result != 0 {
 				return fmt.Errorf("diff failed")
 			}
 
@@ -58,11 +58,14 @@ func NewCmdDiff() *cobra.Command {
 	return cmd
 }
 
-func runDiff(out io.Writer, rc []plan.ResourcePlan, comparers compare.ComparerSet, opts *DiffOptions) int {
+// runDiff compares the planned changes, and the changes made outside of Terraform against the drifted resources rules
+func runDiff(out io.Writer, rc, drift []plan.ResourcePlan, comparers compare.ComparerSet, opts *DiffOptions) int {
 	exitCode := 0
 	createComparer := comparers.CreateComparer
 	destroyComparer := comparers.DestroyComparer
 	updateComparer := comparers.UpdateComparer
+	replaceComparer := comparers.ReplaceComparer
+	readComparer := comparers.ReadComparer
 
 	for _, r := range rc {
 		diff := ""
@@ -71,17 +74,21 @@ func runDiff(out io.Writer, rc []plan.ResourcePlan, comparers compare.ComparerSe
 			diff, pass = createComparer.Diff(r)
 		} else if r.IsDelete() && destroyComparer != nil {
 			diff, pass = destroyComparer.Diff(r)
+		} else if r.IsReplace() && replaceComparer != nil {
+			diff, pass = replaceComparer.Diff(r)
 		} else if r.IsUpdate() && updateComparer != nil {
 			diff, pass = updateComparer.Diff(r)
+		} else if r.IsRead() && readComparer != nil {
+			diff, pass = readComparer.Diff(r)
 		} else {
-			if !opts.Strict {
+			if !opts.Strict || !compare.RequiresComparer(r) {
 				continue
 			}
 
 			if opts.ErrorOnFail {
 				exitCode = 1
 			}
-			fmt.Fprintln(out, fmt.Sprintf("%s %s (no matching comparer)", utils.Yellow("?"), r.GetAddress()))
+			fmt.Fprintln(out, fmt.Sprintf("%s %s (%s)", utils.Yellow("?"), r.GetAddress(), compare.DescribeNoComparer(r)))
 			continue
 		}
 		if pass {
@@ -99,5 +106,32 @@ func runDiff(out io.Writer, rc []plan.ResourcePlan, comparers compare.ComparerSe
 		}
 	}
 
+	if driftComparer := comparers.DriftComparer; driftComparer != nil {
+		for _, r := range drift {
+			diff, pass := driftComparer.Diff(r)
+			if pass && opts.FailedOnly {
+				continue
+			}
+
+			fmt.Fprintln(out, diff)
+			if !pass && opts.ErrorOnFail {
+				exitCode = 1
+			}
+		}
+	}
+
+	// Plan comparers run after every resource has been compared
+	for _, c := range comparers.PlanComparers {
+		diff, pass := c.Diff(rc)
+		if pass && (opts.FailedOnly || diff == "") {
+			continue
+		}
+
+		fmt.Fprintln(out, diff)
+		if !pass && opts.ErrorOnFail {
+			exitCode = 1
+		}
+	}
+
 	return exitCode
 }
@@ -15,6 +15,7 @@ func TestRunDiff(t *testing.T) {
 	cases := map[string]struct {
 		comparers      compare.ComparerSet
 		resourcePlan   []plan.ResourcePlan
+		drift          []plan.ResourcePlan
 		opts           *DiffOptions
 		expected       int
 		expectedOutput []string
@@ -57,6 +58,114 @@ func TestRunDiff(t *testing.T) {
 			expected:       0,
 			expectedOutput: []string{"comparer ok"},
 		},
+		"replace comparer with replace resource": {
+			comparers: compare.ComparerSet{
+				UpdateComparer: &comparefakes.FakeComparer{
+					DiffReturns: true,
+					DiffOutput:  "update comparer ok",
+				},
+				ReplaceComparer: &comparefakes.FakeComparer{
+					DiffReturns: false,
+					DiffOutput:  "replace comparer fail",
+				},
+			},
+			resourcePlan: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					ReplaceReturns: true,
+					AddressReturns: "address",
+					NameReturns:    "name",
+					TypeReturns:    "type",
+				},
+			},
+			opts: &DiffOptions{
+				ErrorOnFail: true,
+			},
+			expected:       1,
+			expectedOutput: []string{"replace comparer fail"},
+		},
+		"read comparer with read resource": {
+			comparers: compare.ComparerSet{
+				ReadComparer: &comparefakes.FakeComparer{
+					DiffReturns: false,
+					DiffOutput:  "read comparer fail",
+				},
+			},
+			resourcePlan: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					ReadReturns:    true,
+					AddressReturns: "data.type.name",
+					NameReturns:    "name",
+					TypeReturns:    "type",
+				},
+			},
+			opts: &DiffOptions{
+				ErrorOnFail: true,
+			},
+			expected:       1,
+			expectedOutput: []string{"read comparer fail"},
+		},
+		"read resource without read comparer with strict enabled": {
+			comparers: compare.ComparerSet{
+				CreateComparer: &comparefakes.FakeComparer{
+					DiffReturns: true,
+				},
+			},
+			resourcePlan: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					ReadReturns:    true,
+					AddressReturns: "data.type.name",
+					NameReturns:    "name",
+					TypeReturns:    "type",
+				},
+			},
+			opts: &DiffOptions{
+				Strict: true,
+			},
+			expected:       0,
+			expectedOutput: []string{"?", "data.type.name (no rules for read resources)"},
+		},
+		"no-op resource with strict enabled": {
+			comparers: compare.ComparerSet{
+				CreateComparer: &comparefakes.FakeComparer{
+					DiffReturns: true,
+				},
+			},
+			resourcePlan: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					NoOpReturns:    true,
+					AddressReturns: "address",
+					NameReturns:    "name",
+					TypeReturns:    "type",
+				},
+			},
+			opts: &DiffOptions{
+				Strict:      true,
+				ErrorOnFail: true,
+			},
+			expected:       1,
+			expectedOutput: []string{"?", "address (no matching comparer)"},
+		},
+		"forgotten resource with strict enabled": {
+			comparers: compare.ComparerSet{
+				CreateComparer: &comparefakes.FakeComparer{
+					DiffReturns: true,
+				},
+			},
+			resourcePlan: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					ForgetReturns:  true,
+					AddressReturns: "address",
+					NameReturns:    "name",
+					TypeReturns:    "type",
+				},
+			},
+			opts: &DiffOptions{
+				Strict:      true,
+				ErrorOnFail: true,
+			},
+			expected:       0,
+			expectedOutput: []string{""},
+		},
 		"no matching comparer": {
 			comparers: compare.ComparerSet{
 				CreateComparer: &comparefakes.FakeComparer{
@@ -74,6 +183,33 @@ func TestRunDiff(t *testing.T) {
 			expected:       0,
 			expectedOutput: []string{""},
 		},
+		"plan comparer fails with error on fail": {
+			comparers: compare.ComparerSet{
+				CreateComparer: &comparefakes.FakeComparer{
+					DiffReturns: true,
+					DiffOutput:  "comparer ok",
+				},
+				PlanComparers: []compare.PlanComparer{
+					&comparefakes.FakePlanComparer{
+						DiffReturns: false,
+						DiffOutput:  "plan rule fail",
+					},
+				},
+			},
+			resourcePlan: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					CreateReturns:  true,
+					AddressReturns: "address",
+					NameReturns:    "name",
+					TypeReturns:    "type",
+				},
+			},
+			opts: &DiffOptions{
+				ErrorOnFail: true,
+			},
+			expected:       1,
+			expectedOutput: []string{"comparer ok", "plan rule fail"},
+		},
 		"no matching comparer with strict enabled": {
 			comparers: compare.ComparerSet{
 				CreateComparer: &comparefakes.FakeComparer{
@@ -210,13 +346,42 @@ func TestRunDiff(t *testing.T) {
 			expected:       0,
 			expectedOutput: []string{"comparer fail"},
 		},
+		"drifted resources are only compared by the drift comparer": {
+			comparers: compare.ComparerSet{
+				UpdateComparer: &comparefakes.FakeComparer{
+					DiffReturns: true,
+					DiffOutput:  "update ok",
+				},
+				DriftComparer: &comparefakes.FakeComparer{
+					DiffReturns: false,
+					DiffOutput:  "drift fail",
+				},
+				PlanComparers: []compare.PlanComparer{
+					&comparefakes.FakePlanComparer{
+						DiffReturns: true,
+						DiffOutput:  "plan ok",
+					},
+				},
+			},
+			drift: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					UpdateReturns:  true,
+					AddressReturns: "address",
+				},
+			},
+			opts: &DiffOptions{
+				ErrorOnFail: true,
+			},
+			expected:       1,
+			expectedOutput: []string{"drift fail", "plan ok"},
+		},
 		// TODO: test case to ensure comparers are called correctly(matching type and number of calls)
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			var output bytes.Buffer
-			if got := runDiff(&output, tc.resourcePlan, tc.comparers, tc.opts); got != tc.expected {
+			if got := runDiff(&output, tc.resourcePlan, tc.drift, tc.comparers, tc.opts); got != tc.expected {
 				t.Errorf("Expected: %v but got %v", tc.expected, got)
 			}
 
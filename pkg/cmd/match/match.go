@@ -22,12 +22,12 @@ type MatchOptions struct {
 func NewCmdMatch() *cobra.Command {
 	opts := &MatchOptions{}
 	cmd := &cobra.Command{
-		Use:   "match <path to ruleset>",
+		Use:   "match <path to ruleset>...",
 		Short: "Outputs resource paths which match the ruleset",
 		Long:  `Outputs resource paths from "terraform plan" which are defined in the ruleset`,
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			comparers, err := compare.NewComparerSet(args[0])
+			comparers, err := compare.NewComparerSet(args...)
 			if err != nil {
 				return err
 			}
@@ -57,6 +57,8 @@ func runMatch(out io.Writer, rc []plan.ResourcePlan, comparers compare.ComparerS
 	createComparer := comparers.CreateComparer
 	destroyComparer := comparers.DestroyComparer
 	updateComparer := comparers.UpdateComparer
+	replaceComparer := comparers.ReplaceComparer
+	readComparer := comparers.ReadComparer
 
 	var matches []string
 	for _, r := range rc {
@@ -65,8 +67,12 @@ func runMatch(out io.Writer, rc []plan.ResourcePlan, comparers compare.ComparerS
 			match = createComparer.Compare(r)
 		} else if r.IsDelete() && destroyComparer != nil {
 			match = destroyComparer.Compare(r)
+		} else if r.IsReplace() && replaceComparer != nil {
+			match = replaceComparer.Compare(r)
 		} else if r.IsUpdate() && updateComparer != nil {
 			match = updateComparer.Compare(r)
+		} else if r.IsRead() && readComparer != nil {
+			match = readComparer.Compare(r)
 		}
 		if (!opts.Invert && match) || (opts.Invert && !match) {
 			matches = append(matches, r.GetAddress())
@@ -12,21 +12,21 @@ import (
 
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "validate <path to ruleset>",
+		Use:   "validate <path to ruleset>...",
 		Short: "Validte the ruleset",
 		Long:  "Validate the ruleset, exiting with code 0 if the ruleset is valid",
 
-		// NOTE: We explicitly do not set Args with ExactArgs(1) since that
+		// NOTE: We explicitly do not set Args with MinimumNArgs(1) since that
 		// will not print the help message.
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) != 1 {
+			if len(args) == 0 {
 				cmd.Help()
 				os.Exit(1)
 			}
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ruleset, err := ruleset.ParseRuleset(args[0])
+			ruleset, err := ruleset.ParseRuleset(args...)
 			if err != nil {
 				return fmt.Errorf("Could not parse ruleset: %v", err)
 			}
@@ -0,0 +1,116 @@
+package compare
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/drlau/akashi/pkg/plan"
+	"github.com/drlau/akashi/pkg/ruleset"
+	"github.com/drlau/akashi/pkg/utils"
+)
+
+// BudgetComparer compares the number of resources with each action in the plan against the budgets
+type BudgetComparer struct {
+	Budgets []budget
+}
+
+type budget struct {
+	// Name is the name of the budget in the ruleset, such as maxDestroyed
+	Name   string
+	Type   string
+	Count  int
+	counts func(plan.ResourcePlan) bool
+}
+
+// NewBudgetComparer returns a comparer for the budgets of the ruleset
+// Replaced resources count towards the created and destroyed budgets, as terraform destroys and creates them
+func NewBudgetComparer(rs ruleset.Ruleset) (*BudgetComparer, error) {
+	var result []budget
+	sections := []struct {
+		name    string
+		budgets ruleset.Budgets
+		counts  func(plan.ResourcePlan) bool
+	}{
+		{"maxCreated", rs.MaxCreated, func(r plan.ResourcePlan) bool { return r.IsCreate() || r.IsReplace() }},
+		{"maxDestroyed", rs.MaxDestroyed, func(r plan.ResourcePlan) bool { return r.IsDelete() || r.IsReplace() }},
+		{"maxReplaced", rs.MaxReplaced, func(r plan.ResourcePlan) bool { return r.IsReplace() }},
+	}
+	for _, s := range sections {
+		for _, b := range s.budgets {
+			if b.Count == nil {
+				return nil, fmt.Errorf("%s: %s: missing count", s.name, b.String())
+			}
+			if *b.Count < 0 {
+				return nil, fmt.Errorf("%s: %s: negative count %d", s.name, b.String(), *b.Count)
+			}
+
+			result = append(result, budget{
+				Name:   s.name,
+				Type:   b.Type,
+				Count:  *b.Count,
+				counts: s.counts,
+			})
+		}
+	}
+
+	return &BudgetComparer{
+		Budgets: result,
+	}, nil
+}
+
+func (c *BudgetComparer) Compare(rc []plan.ResourcePlan) bool {
+	for _, b := range c.Budgets {
+		if len(b.resources(rc)) > b.Count {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *BudgetComparer) Diff(rc []plan.ResourcePlan) (string, bool) {
+	var (
+		result []string
+		equal  = true
+	)
+
+	for _, b := range c.Budgets {
+		resources := b.resources(rc)
+		if len(resources) <= b.Count {
+			result = append(result, fmt.Sprintf("%s %s", utils.Green("✓"), b.describe(len(resources))))
+			continue
+		}
+
+		equal = false
+		result = append(result, fmt.Sprintf("%s %s", utils.Red("×"), utils.Red(b.describe(len(resources)))))
+		for _, r := range resources {
+			result = append(result, utils.Red(fmt.Sprintf("  - %s", r.GetAddress())))
+		}
+	}
+
+	return strings.Join(result, "\n"), equal
+}
+
+// resources returns the resources counted by the budget
+func (b budget) resources(rc []plan.ResourcePlan) []plan.ResourcePlan {
+	var result []plan.ResourcePlan
+	for _, r := range rc {
+		if b.Type != "" && b.Type != r.GetType() {
+			continue
+		}
+		if b.counts(r) {
+			result = append(result, r)
+		}
+	}
+
+	return result
+}
+
+// describe describes the number of resources counted against the budget, for example "2 of at most 0 aws_db_instance (maxDestroyed)"
+func (b budget) describe(count int) string {
+	resources := "resources"
+	if b.Type != "" {
+		resources = b.Type
+	}
+	return fmt.Sprintf("%d of at most %d %s (%s)", count, b.Count, resources, b.Name)
+}
@@ -0,0 +1,137 @@
+package compare
+
+import (
+	"strings"
+	"testing"
+
+	planfakes "github.com/drlau/akashi/pkg/compare/fakes"
+	"github.com/drlau/akashi/pkg/plan"
+	"github.com/drlau/akashi/pkg/ruleset"
+)
+
+func TestBudgetComparerDiff(t *testing.T) {
+	resourcePlan := []plan.ResourcePlan{
+		&planfakes.FakeResourcePlan{
+			AddressReturns: "aws_instance.web[0]",
+			TypeReturns:    "aws_instance",
+			DeleteReturns:  true,
+		},
+		&planfakes.FakeResourcePlan{
+			AddressReturns: "aws_instance.web[1]",
+			TypeReturns:    "aws_instance",
+			DeleteReturns:  true,
+		},
+		&planfakes.FakeResourcePlan{
+			AddressReturns: "aws_db_instance.main",
+			TypeReturns:    "aws_db_instance",
+			ReplaceReturns: true,
+		},
+		&planfakes.FakeResourcePlan{
+			AddressReturns: "aws_s3_bucket.logs",
+			TypeReturns:    "aws_s3_bucket",
+			CreateReturns:  true,
+		},
+	}
+
+	cases := map[string]struct {
+		ruleset           ruleset.Ruleset
+		expected          bool
+		expectedOutput    []string
+		unexpectedOutputs []string
+	}{
+		"within budgets": {
+			ruleset: ruleset.Ruleset{
+				MaxCreated:   ruleset.Budgets{{Count: intPointer(2)}},
+				MaxDestroyed: ruleset.Budgets{{Count: intPointer(3)}},
+				MaxReplaced:  ruleset.Budgets{{Count: intPointer(1)}},
+			},
+			expected: true,
+			expectedOutput: []string{
+				"2 of at most 2 resources (maxCreated)",
+				"3 of at most 3 resources (maxDestroyed)",
+				"1 of at most 1 resources (maxReplaced)",
+			},
+		},
+		"destroyed over budget": {
+			ruleset: ruleset.Ruleset{
+				MaxDestroyed: ruleset.Budgets{{Count: intPointer(0)}},
+			},
+			expected: false,
+			expectedOutput: []string{
+				"3 of at most 0 resources (maxDestroyed)",
+				"- aws_instance.web[0]",
+				"- aws_instance.web[1]",
+				"- aws_db_instance.main",
+			},
+			unexpectedOutputs: []string{"aws_s3_bucket.logs"},
+		},
+		"budget for type": {
+			ruleset: ruleset.Ruleset{
+				MaxDestroyed: ruleset.Budgets{
+					{Type: "aws_db_instance", Count: intPointer(0)},
+					{Type: "aws_instance", Count: intPointer(2)},
+				},
+			},
+			expected: false,
+			expectedOutput: []string{
+				"1 of at most 0 aws_db_instance (maxDestroyed)",
+				"- aws_db_instance.main",
+				"2 of at most 2 aws_instance (maxDestroyed)",
+			},
+		},
+		"replaced over budget": {
+			ruleset: ruleset.Ruleset{
+				MaxReplaced: ruleset.Budgets{{Count: intPointer(0)}},
+			},
+			expected:          false,
+			expectedOutput:    []string{"1 of at most 0 resources (maxReplaced)", "- aws_db_instance.main"},
+			unexpectedOutputs: []string{"aws_instance.web"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			comparer, err := NewBudgetComparer(tc.ruleset)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			output, got := comparer.Diff(resourcePlan)
+			if got != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+			if compared := comparer.Compare(resourcePlan); compared != tc.expected {
+				t.Errorf("Expected compare: %v but got %v", tc.expected, compared)
+			}
+			for _, o := range tc.expectedOutput {
+				if !strings.Contains(output, o) {
+					t.Errorf("Output %s did not contain expected string %s", output, o)
+				}
+			}
+			for _, o := range tc.unexpectedOutputs {
+				if strings.Contains(output, o) {
+					t.Errorf("Output %s contained unexpected string %s", output, o)
+				}
+			}
+		})
+	}
+}
+
+func TestNewBudgetComparerInvalidCount(t *testing.T) {
+	cases := map[string]ruleset.Ruleset{
+		"missing count": {
+			MaxDestroyed: ruleset.Budgets{{Type: "aws_db_instance"}},
+		},
+		"negative count": {
+			MaxCreated: ruleset.Budgets{{Count: intPointer(-1)}},
+		},
+	}
+
+	for name, rs := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewBudgetComparer(rs); err == nil {
+				t.Errorf("Expected an error for an invalid budget")
+			}
+		})
+	}
+}
@@ -2,9 +2,14 @@ package compare
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/drlau/akashi/pkg/plan"
 	"github.com/drlau/akashi/pkg/resource"
+	"github.com/drlau/akashi/pkg/ruleset"
+	"github.com/drlau/akashi/pkg/utils"
 )
 
 type Resource interface {
@@ -16,3 +21,160 @@ type Resource interface {
 func constructNameTypeKey(r plan.ResourcePlan) string {
 	return fmt.Sprintf("%s.%s", r.GetType(), r.GetName())
 }
+
+// constructRuleKey prefixes the key with the module and appends the formatted index
+// Empty modules and nil indexes are omitted
+func constructRuleKey(key, module string, index interface{}) string {
+	if module != "" {
+		key = fmt.Sprintf("%s.%s", module, key)
+	}
+	return key + ruleset.FormatIndex(index)
+}
+
+// moduleCandidates returns the module rules that can match the module address, in order of priority:
+// the exact module, then the module and each parent module with the wildcard suffix, then no module
+func moduleCandidates(moduleAddress string) []string {
+	if moduleAddress == "" {
+		return []string{""}
+	}
+
+	result := []string{moduleAddress}
+	values := plan.SplitAddress(moduleAddress)
+	for i := len(values); i >= 2; i -= 2 {
+		parent := strings.Join(values[:i], ".")
+		result = append(result, parent+ruleset.ModuleWildcardSuffix)
+
+		// module.vpc["a"].* is more specific than module.vpc.*
+		if name := values[i-1]; strings.Contains(name, "[") {
+			values[i-1] = name[:strings.Index(name, "[")]
+			result = append(result, strings.Join(values[:i], ".")+ruleset.ModuleWildcardSuffix)
+		}
+	}
+
+	return append(result, "")
+}
+
+// matchedResource is a rule that matched a resource plan
+// Rule identifies the rule in diff output
+type matchedResource[T any] struct {
+	Rule     string
+	Resource T
+}
+
+// lookupResources finds the rules for key, in order of priority
+// Rules for the resource's module are preferred over rules without a module, and
+// for each module, a rule for the exact index is preferred, then a rule with the wildcard index, then a rule without an index
+func lookupResources[T any](resources map[string]T, key, moduleAddress string, index interface{}) []matchedResource[T] {
+	var result []matchedResource[T]
+	for _, module := range moduleCandidates(moduleAddress) {
+		var keys []string
+		if index != nil {
+			keys = append(keys, constructRuleKey(key, module, index), constructRuleKey(key, module, ruleset.IndexWildcard))
+		}
+		keys = append(keys, constructRuleKey(key, module, nil))
+
+		for _, k := range keys {
+			if r, ok := resources[k]; ok {
+				result = append(result, matchedResource[T]{
+					Rule:     k,
+					Resource: r,
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+type addressResource[T any] struct {
+	Rule        string
+	Pattern     *regexp.Regexp
+	Specificity int
+	Resource    T
+
+	// Exact is true for globs without wildcards, which only match a single address
+	Exact bool
+}
+
+// newAddressResource compiles the address pattern of the resource identifier
+// Globs are more specific the more literal characters they have, and regexes are less specific than any glob
+func newAddressResource[T any](id ruleset.ResourceIdentifier, res T) (addressResource[T], error) {
+	pattern, err := id.AddressPattern()
+	if err != nil {
+		return addressResource[T]{}, fmt.Errorf("invalid address pattern for %s: %v", id.String(), err)
+	}
+
+	specificity := -1
+	if id.AddressRegex == "" {
+		specificity = len(strings.NewReplacer("*", "", "?", "").Replace(id.Address))
+	}
+
+	return addressResource[T]{
+		Rule:        id.String(),
+		Pattern:     pattern,
+		Specificity: specificity,
+		Resource:    res,
+		Exact:       id.AddressRegex == "" && !strings.ContainsAny(id.Address, "*?"),
+	}, nil
+}
+
+// sortAddressResources orders the address resources from most to least specific
+// Resources with the same specificity keep the order they were defined in the ruleset
+func sortAddressResources[T any](resources []addressResource[T]) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resources[i].Specificity > resources[j].Specificity
+	})
+}
+
+// lookupAddressResources returns every resource whose pattern matches the address
+// If exact is set, only exact addresses are returned, otherwise only globs with wildcards and regexes
+// resources should be sorted with sortAddressResources
+func lookupAddressResources[T any](resources []addressResource[T], address string, exact bool) []matchedResource[T] {
+	var result []matchedResource[T]
+	for _, r := range resources {
+		if r.Exact == exact && r.Pattern.MatchString(address) {
+			result = append(result, matchedResource[T]{
+				Rule:     r.Rule,
+				Resource: r.Resource,
+			})
+		}
+	}
+
+	return result
+}
+
+// matchResources combines the matched rules in the order they are passed, which is their priority
+// Comparers pass exact addresses, then name and type, then name, then type, then the other address patterns
+// Unless matchAll is set, only the first matching rule is returned
+func matchResources[T any](matchAll bool, matches ...[]matchedResource[T]) []matchedResource[T] {
+	var result []matchedResource[T]
+	for _, m := range matches {
+		result = append(result, m...)
+	}
+
+	if !matchAll && len(result) > 1 {
+		return result[:1]
+	}
+	return result
+}
+
+// formatFailedAddress formats the address of a resource that failed a rule
+// If every matching rule is applied, the rule is included to group failures by rule
+func formatFailedAddress(address, rule string, matchAll bool) string {
+	if !matchAll {
+		return fmt.Sprintf("%s %s", utils.Red("×"), utils.Red(address))
+	}
+	return fmt.Sprintf("%s %s %s", utils.Red("×"), utils.Red(address), utils.Red(fmt.Sprintf("(rule: %s)", rule)))
+}
+
+// newMatchAll returns true if every matching rule should be applied
+func newMatchAll(matchMode string) (bool, error) {
+	switch matchMode {
+	case "", ruleset.MatchModeFirst:
+		return false, nil
+	case ruleset.MatchModeAll:
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown matchMode %q", matchMode)
+	}
+}
@@ -0,0 +1,180 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/drlau/akashi/pkg/ruleset"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLookupAddressResource(t *testing.T) {
+	cases := map[string]struct {
+		identifiers []ruleset.ResourceIdentifier
+		address     string
+		expected    string
+		found       bool
+	}{
+		"exact address": {
+			identifiers: []ruleset.ResourceIdentifier{
+				{Address: "module.vpc.type.name"},
+			},
+			address:  "module.vpc.type.name",
+			expected: "module.vpc.type.name",
+			found:    true,
+		},
+		"glob does not match across separators": {
+			identifiers: []ruleset.ResourceIdentifier{
+				{Address: "module.*.type.name"},
+			},
+			address: "module.network.module.subnets.type.name",
+			found:   false,
+		},
+		"double star glob matches across separators": {
+			identifiers: []ruleset.ResourceIdentifier{
+				{Address: "module.**.type.name"},
+			},
+			address:  "module.network.module.subnets.type.name",
+			expected: "module.**.type.name",
+			found:    true,
+		},
+		"glob matches index": {
+			identifiers: []ruleset.ResourceIdentifier{
+				{Address: "module.*.aws_iam_*.*"},
+			},
+			address:  `module.iam.aws_iam_role.name["blue"]`,
+			expected: "module.*.aws_iam_*.*",
+			found:    true,
+		},
+		"prioritizes most specific glob": {
+			identifiers: []ruleset.ResourceIdentifier{
+				{Address: "module.*.*.*"},
+				{Address: "module.*.aws_iam_*.*"},
+				{AddressRegex: "^module\\.iam\\..*$"},
+			},
+			address:  "module.iam.aws_iam_role.name",
+			expected: "module.*.aws_iam_*.*",
+			found:    true,
+		},
+		"prioritizes glob over regex": {
+			identifiers: []ruleset.ResourceIdentifier{
+				{AddressRegex: "^module\\.iam\\.aws_iam_role\\.name$"},
+				{Address: "**"},
+			},
+			address:  "module.iam.aws_iam_role.name",
+			expected: "**",
+			found:    true,
+		},
+		"prioritizes exact address": {
+			identifiers: []ruleset.ResourceIdentifier{
+				{Address: "module.iam.*.name"},
+				{Address: "module.iam.aws_iam_role.name"},
+			},
+			address:  "module.iam.aws_iam_role.name",
+			expected: "module.iam.aws_iam_role.name",
+			found:    true,
+		},
+		"keeps ruleset order for equal specificity": {
+			identifiers: []ruleset.ResourceIdentifier{
+				{AddressRegex: "role"},
+				{AddressRegex: "iam"},
+			},
+			address:  "module.iam.aws_iam_role.name",
+			expected: "/role/",
+			found:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var resources []addressResource[string]
+			for _, id := range tc.identifiers {
+				ar, err := newAddressResource(id, id.String())
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				resources = append(resources, ar)
+			}
+			sortAddressResources(resources)
+
+			matches := append(lookupAddressResources(resources, tc.address, true), lookupAddressResources(resources, tc.address, false)...)
+			if found := len(matches) > 0; found != tc.found {
+				t.Fatalf("Expected found: %v but got %v", tc.found, found)
+			}
+			if tc.found && matches[0].Resource != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, matches[0].Resource)
+			}
+		})
+	}
+}
+
+func TestNewAddressResourceInvalidRegex(t *testing.T) {
+	if _, err := newAddressResource(ruleset.ResourceIdentifier{AddressRegex: "("}, ""); err == nil {
+		t.Errorf("Expected error for invalid regex")
+	}
+}
+
+func TestLookupResources(t *testing.T) {
+	cases := map[string]struct {
+		resources map[string]string
+		module    string
+		index     interface{}
+		expected  []string
+	}{
+		"no matches": {
+			resources: map[string]string{
+				"type.other": "",
+			},
+			expected: nil,
+		},
+		"orders by module then index": {
+			resources: map[string]string{
+				"type.name":                  "",
+				"type.name[1]":               "",
+				"module.network.*.type.name": "",
+				"module.network.module.subnets.type.name[*]": "",
+				"module.network.module.subnets.type.name":    "",
+			},
+			module: "module.network.module.subnets",
+			index:  1,
+			expected: []string{
+				"module.network.module.subnets.type.name[*]",
+				"module.network.module.subnets.type.name",
+				"module.network.*.type.name",
+				"type.name[1]",
+				"type.name",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, m := range lookupResources(tc.resources, "type.name", tc.module, tc.index) {
+				got = append(got, m.Rule)
+			}
+			if diff := cmp.Diff(got, tc.expected); diff != "" {
+				t.Errorf("(-got, +expected)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMatchResources(t *testing.T) {
+	first := []matchedResource[string]{{Rule: "address"}}
+	second := []matchedResource[string]{{Rule: "type.name"}, {Rule: "type"}}
+
+	if got := matchResources(false, nil, second, first); len(got) != 1 || got[0].Rule != "type.name" {
+		t.Errorf("Expected only the first match but got %v", got)
+	}
+	if got := matchResources(true, first, nil, second); len(got) != 3 {
+		t.Errorf("Expected every match but got %v", got)
+	}
+}
+
+func intPointer(i int) *int {
+	return &i
+}
+
+func boolPointer(b bool) *bool {
+	return &b
+}
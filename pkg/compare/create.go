@@ -2,6 +2,7 @@ package compare
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/drlau/akashi/pkg/plan"
 	"github.com/drlau/akashi/pkg/resource"
@@ -12,15 +13,23 @@ import (
 // TODO: Create and destroy are nearly identical
 // depending on how updated resources comparison is implemented, move common logic to internal struct
 type CreateComparer struct {
-	Strict bool
+	Strict   bool
+	MatchAll bool
 
+	AddressResources  []addressResource[Resource]
 	NameResources     map[string]Resource
 	TypeResources     map[string]Resource
 	NameTypeResources map[string]Resource
 }
 
-func NewCreateComparer(ruleset ruleset.CreateDeleteResourceChanges) *CreateComparer {
+func NewCreateComparer(ruleset ruleset.CreateDeleteResourceChanges) (*CreateComparer, error) {
+	matchAll, err := newMatchAll(ruleset.MatchMode)
+	if err != nil {
+		return nil, err
+	}
+
 	defaultOptions := resource.NewCompareOptions(ruleset.Default)
+	var addressResources []addressResource[Resource]
 	nameTypeResources := make(map[string]Resource)
 	typeResources := make(map[string]Resource)
 	nameResources := make(map[string]Resource)
@@ -28,56 +37,61 @@ func NewCreateComparer(ruleset ruleset.CreateDeleteResourceChanges) *CreateCompa
 	// Iterate over all the resources
 	for _, r := range ruleset.Resources {
 		res := resource.NewResourceFromConfig(r.ResourceIdentifier, r.ResourceRules, &r.CompareOptions, defaultOptions)
-		if r.Name != "" && r.Type != "" {
+		if r.IsAddressPattern() {
+			ar, err := newAddressResource[Resource](r.ResourceIdentifier, res)
+			if err != nil {
+				return nil, err
+			}
+			addressResources = append(addressResources, ar)
+		} else if r.Name != "" && r.Type != "" {
 			// format name and type key
-			nameTypeResources[fmt.Sprintf("%s.%s", r.Type, r.Name)] = res
+			nameTypeResources[constructRuleKey(fmt.Sprintf("%s.%s", r.Type, r.Name), r.Module, r.Index)] = res
 		} else if r.Name != "" {
-			nameResources[r.Name] = res
+			nameResources[constructRuleKey(r.Name, r.Module, r.Index)] = res
 		} else if r.Type != "" {
-			typeResources[r.Type] = res
+			typeResources[constructRuleKey(r.Type, r.Module, r.Index)] = res
 		}
 	}
+	sortAddressResources(addressResources)
+
 	return &CreateComparer{
 		Strict:            ruleset.Strict,
+		MatchAll:          matchAll,
+		AddressResources:  addressResources,
 		NameResources:     nameResources,
 		TypeResources:     typeResources,
 		NameTypeResources: nameTypeResources,
-	}
+	}, nil
 }
 
 func (c *CreateComparer) Compare(r plan.ResourcePlan) bool {
-	nameType := constructNameTypeKey(r)
 	changes := resource.ResourceValues{
 		Values:   r.GetAfter(),
 		Computed: r.GetComputed(),
 	}
 
-	if ro, ok := c.NameTypeResources[nameType]; ok {
-		return ro.Compare(changes)
-	} else if ro, ok := c.NameResources[r.GetName()]; ok {
-		return ro.Compare(changes)
-	} else if ro, ok := c.TypeResources[r.GetType()]; ok {
-		return ro.Compare(changes)
+	matches := c.match(r)
+	if len(matches) == 0 {
+		return !c.Strict
+	}
+
+	for _, m := range matches {
+		if !m.Resource.Compare(changes) {
+			return false
+		}
 	}
 
-	return !c.Strict
+	return true
 }
 
 func (c *CreateComparer) Diff(r plan.ResourcePlan) (string, bool) {
-	nameType := constructNameTypeKey(r)
 	changes := resource.ResourceValues{
 		Values:   r.GetAfter(),
 		Computed: r.GetComputed(),
 	}
 
-	var ro Resource
-	if rs, ok := c.NameTypeResources[nameType]; ok {
-		ro = rs
-	} else if rs, ok := c.NameResources[r.GetName()]; ok {
-		ro = rs
-	} else if rs, ok := c.TypeResources[r.GetType()]; ok {
-		ro = rs
-	} else {
+	matches := c.match(r)
+	if len(matches) == 0 {
 		if c.Strict {
 			return fmt.Sprintf("%s %s (no matching rule)", utils.Red("×"), r.GetAddress()), false
 		}
@@ -85,10 +99,32 @@ func (c *CreateComparer) Diff(r plan.ResourcePlan) (string, bool) {
 		return fmt.Sprintf("%s %s (no matching rule)", utils.Yellow("!"), r.GetAddress()), true
 	}
 
-	diff := ro.Diff(changes)
-	if diff != "" {
-		return fmt.Sprintf("%s %s\n%s", utils.Red("×"), utils.Red(r.GetAddress()), diff), false
+	var failures []string
+	for _, m := range matches {
+		if diff := m.Resource.Diff(changes); diff != "" {
+			failures = append(failures, fmt.Sprintf("%s\n%s", formatFailedAddress(r.GetAddress(), m.Rule, c.MatchAll), diff))
+		}
+	}
+
+	if len(failures) > 0 {
+		return strings.Join(failures, "\n"), false
 	}
 
 	return fmt.Sprintf("%s %s", utils.Green("✓"), r.GetAddress()), true
 }
+
+// match returns the rules for the resource
+// Exact addresses are checked first, then name and type, then name, then type, then the other address patterns
+func (c *CreateComparer) match(r plan.ResourcePlan) []matchedResource[Resource] {
+	module := r.GetModuleAddress()
+	index := r.GetIndex()
+
+	return matchResources(
+		c.MatchAll,
+		lookupAddressResources(c.AddressResources, r.GetAddress(), true),
+		lookupResources(c.NameTypeResources, constructNameTypeKey(r), module, index),
+		lookupResources(c.NameResources, r.GetName(), module, index),
+		lookupResources(c.TypeResources, r.GetType(), module, index),
+		lookupAddressResources(c.AddressResources, r.GetAddress(), false),
+	)
+}
@@ -1,6 +1,7 @@
 package compare
 
 import (
+	"regexp"
 	"strings"
 	"testing"
 
@@ -95,6 +96,208 @@ func TestDestroyCompare(t *testing.T) {
 			},
 			expected: true,
 		},
+		"matching index resource": {
+			comparer: &DestroyComparer{
+				NameTypeResources: map[string]Resource{
+					"type.name[\"blue\"]": &comparefakes.FakeResource{
+						CompareReturns: true,
+					},
+				},
+			},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				NameReturns:  "name",
+				TypeReturns:  "type",
+				IndexReturns: "blue",
+			},
+			expected: true,
+		},
+		"matching wildcard index resource": {
+			comparer: &DestroyComparer{
+				NameTypeResources: map[string]Resource{
+					"type.name[*]": &comparefakes.FakeResource{
+						CompareReturns: true,
+					},
+				},
+			},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				NameReturns:  "name",
+				TypeReturns:  "type",
+				IndexReturns: 2,
+			},
+			expected: true,
+		},
+		"prioritizes matching index resource": {
+			comparer: &DestroyComparer{
+				NameTypeResources: map[string]Resource{
+					"type.name[2]": &comparefakes.FakeResource{
+						CompareReturns: true,
+					},
+					"type.name[*]": &comparefakes.FakeResource{
+						CompareReturns: false,
+					},
+					"type.name": &comparefakes.FakeResource{
+						CompareReturns: false,
+					},
+				},
+			},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				NameReturns:  "name",
+				TypeReturns:  "type",
+				IndexReturns: 2,
+			},
+			expected: true,
+		},
+		"index resource does not match other index": {
+			comparer: &DestroyComparer{
+				Strict: true,
+				NameTypeResources: map[string]Resource{
+					"type.name[2]": &comparefakes.FakeResource{
+						CompareReturns: true,
+					},
+				},
+			},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				NameReturns:  "name",
+				TypeReturns:  "type",
+				IndexReturns: 1,
+			},
+			expected: false,
+		},
+		"falls back to resource without index": {
+			comparer: &DestroyComparer{
+				TypeResources: map[string]Resource{
+					"type": &comparefakes.FakeResource{
+						CompareReturns: true,
+					},
+				},
+			},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				NameReturns:  "name",
+				TypeReturns:  "type",
+				IndexReturns: 1,
+			},
+			expected: true,
+		},
+		"matching module resource": {
+			comparer: &DestroyComparer{
+				NameTypeResources: map[string]Resource{
+					"module.vpc.type.name": &comparefakes.FakeResource{
+						CompareReturns: true,
+					},
+					"type.name": &comparefakes.FakeResource{
+						CompareReturns: false,
+					},
+				},
+			},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				NameReturns:   "name",
+				TypeReturns:   "type",
+				ModuleReturns: "module.vpc",
+			},
+			expected: true,
+		},
+		"matching module prefix resource": {
+			comparer: &DestroyComparer{
+				TypeResources: map[string]Resource{
+					"module.network.*.type": &comparefakes.FakeResource{
+						CompareReturns: true,
+					},
+					"type": &comparefakes.FakeResource{
+						CompareReturns: false,
+					},
+				},
+			},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				NameReturns:   "name",
+				TypeReturns:   "type",
+				ModuleReturns: "module.network.module.subnets",
+			},
+			expected: true,
+		},
+		"module resource does not match root resource": {
+			comparer: &DestroyComparer{
+				Strict: true,
+				NameTypeResources: map[string]Resource{
+					"module.vpc.type.name": &comparefakes.FakeResource{
+						CompareReturns: true,
+					},
+				},
+			},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				NameReturns: "name",
+				TypeReturns: "type",
+			},
+			expected: false,
+		},
+		"prioritizes name and type over address pattern": {
+			comparer: &DestroyComparer{
+				AddressResources: []addressResource[Resource]{
+					{
+						Pattern: regexp.MustCompile("^module\\.vpc\\..*$"),
+						Resource: &comparefakes.FakeResource{
+							CompareReturns: true,
+						},
+					},
+				},
+				NameTypeResources: map[string]Resource{
+					"type.name": &comparefakes.FakeResource{
+						CompareReturns: false,
+					},
+				},
+			},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				AddressReturns: "module.vpc.type.name",
+				NameReturns:    "name",
+				TypeReturns:    "type",
+				ModuleReturns:  "module.vpc",
+			},
+			expected: false,
+		},
+		"prioritizes exact address": {
+			comparer: &DestroyComparer{
+				AddressResources: []addressResource[Resource]{
+					{
+						Pattern: regexp.MustCompile("^module\\.vpc\\.type\\.name$"),
+						Exact:   true,
+						Resource: &comparefakes.FakeResource{
+							CompareReturns: true,
+						},
+					},
+				},
+				NameTypeResources: map[string]Resource{
+					"type.name": &comparefakes.FakeResource{
+						CompareReturns: false,
+					},
+				},
+			},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				AddressReturns: "module.vpc.type.name",
+				NameReturns:    "name",
+				TypeReturns:    "type",
+				ModuleReturns:  "module.vpc",
+			},
+			expected: true,
+		},
+		"match all applies every matching resource": {
+			comparer: &DestroyComparer{
+				MatchAll: true,
+				NameTypeResources: map[string]Resource{
+					"type.name": &comparefakes.FakeResource{
+						CompareReturns: true,
+					},
+				},
+				TypeResources: map[string]Resource{
+					"type": &comparefakes.FakeResource{
+						CompareReturns: false,
+					},
+				},
+			},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				NameReturns: "name",
+				TypeReturns: "type",
+			},
+			expected: false,
+		},
 		"no matching resource": {
 			comparer: &DestroyComparer{},
 			resourcePlan: &planfakes.FakeResourcePlan{
@@ -221,6 +424,33 @@ func TestDestroyDiff(t *testing.T) {
 			expected:       true,
 			expectedOutput: []string{""},
 		},
+		"match all reports failures by resource": {
+			comparer: &DestroyComparer{
+				MatchAll: true,
+				NameTypeResources: map[string]Resource{
+					"type.name": &comparefakes.FakeResource{
+						DiffReturns: "",
+					},
+				},
+				NameResources: map[string]Resource{
+					"name": &comparefakes.FakeResource{
+						DiffReturns: "name failed",
+					},
+				},
+				TypeResources: map[string]Resource{
+					"type": &comparefakes.FakeResource{
+						DiffReturns: "type failed",
+					},
+				},
+			},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				AddressReturns: "address",
+				NameReturns:    "name",
+				TypeReturns:    "type",
+			},
+			expected:       false,
+			expectedOutput: []string{"(rule: name)", "name failed", "(rule: type)", "type failed"},
+		},
 		"no matching resource": {
 			comparer: &DestroyComparer{},
 			resourcePlan: &planfakes.FakeResourcePlan{
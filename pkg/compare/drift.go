@@ -0,0 +1,71 @@
+package compare
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/drlau/akashi/pkg/plan"
+	"github.com/drlau/akashi/pkg/ruleset"
+	"github.com/drlau/akashi/pkg/utils"
+)
+
+// DriftComparer compares the changes made outside of Terraform against the drifted resources rules
+// Before rules are compared against the values in the state, and after rules against the values changed outside of Terraform
+type DriftComparer struct {
+	*UpdateComparer
+}
+
+// NewDriftComparer returns a comparer for changes made outside of Terraform
+// Unless ignoreNoOp is disabled, only the arguments that changed outside of Terraform are compared
+func NewDriftComparer(rules ruleset.UpdateResourceChanges) (*DriftComparer, error) {
+	defaults := ruleset.CompareOptions{}
+	if rules.Default != nil {
+		defaults = *rules.Default
+	}
+	if defaults.IgnoreNoOp == nil {
+		ignoreNoOp := true
+		defaults.IgnoreNoOp = &ignoreNoOp
+	}
+	rules.Default = &defaults
+
+	c, err := NewUpdateComparer(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DriftComparer{UpdateComparer: c}, nil
+}
+
+// Diff compares the drifted resource, and lists the arguments that changed outside of Terraform
+func (c *DriftComparer) Diff(r plan.ResourcePlan) (string, bool) {
+	diff, equal := c.UpdateComparer.Diff(r)
+	if r.IsDelete() {
+		return fmt.Sprintf("%s\n%s", diff, utils.Yellow("  deleted outside of Terraform")), equal
+	}
+
+	paths := changedPaths("", r.GetAfterChangedOnly())
+	if len(paths) == 0 {
+		return diff, equal
+	}
+	return fmt.Sprintf("%s\n%s", diff, utils.Yellow(fmt.Sprintf("  changed outside of Terraform: %s", strings.Join(paths, ", ")))), equal
+}
+
+// changedPaths returns the sorted paths of every changed value, with nested maps joined by "."
+func changedPaths(prefix string, values map[string]interface{}) []string {
+	var result []string
+	for k, v := range values {
+		path := k
+		if prefix != "" {
+			path = fmt.Sprintf("%s.%s", prefix, k)
+		}
+
+		if nested, ok := v.(map[string]interface{}); ok && len(nested) > 0 {
+			result = append(result, changedPaths(path, nested)...)
+			continue
+		}
+		result = append(result, path)
+	}
+	sort.Strings(result)
+	return result
+}
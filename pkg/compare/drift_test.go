@@ -0,0 +1,103 @@
+package compare
+
+import (
+	"strings"
+	"testing"
+
+	planfakes "github.com/drlau/akashi/pkg/compare/fakes"
+	"github.com/drlau/akashi/pkg/ruleset"
+)
+
+func TestDriftDiff(t *testing.T) {
+	cases := map[string]struct {
+		resourcePlan   *planfakes.FakeResourcePlan
+		expected       bool
+		expectedOutput []string
+	}{
+		"ignored argument drifted": {
+			resourcePlan: &planfakes.FakeResourcePlan{
+				TypeReturns:    "aws_autoscaling_group",
+				AddressReturns: "aws_autoscaling_group.web",
+				BeforeReturns:  map[string]interface{}{"desired_capacity": float64(2)},
+				AfterReturns:   map[string]interface{}{"desired_capacity": float64(5)},
+			},
+			expected:       true,
+			expectedOutput: []string{"✓", "aws_autoscaling_group.web", "changed outside of Terraform: desired_capacity"},
+		},
+		"other argument drifted": {
+			resourcePlan: &planfakes.FakeResourcePlan{
+				TypeReturns:    "aws_autoscaling_group",
+				AddressReturns: "aws_autoscaling_group.web",
+				BeforeReturns: map[string]interface{}{
+					"desired_capacity": float64(2),
+					"tags":             map[string]interface{}{"Owner": "platform"},
+				},
+				AfterReturns: map[string]interface{}{
+					"tags": map[string]interface{}{"Owner": "someone"},
+				},
+			},
+			expected:       false,
+			expectedOutput: []string{"tags", "changed outside of Terraform: tags.Owner"},
+		},
+		"enforced argument drifted": {
+			resourcePlan: &planfakes.FakeResourcePlan{
+				TypeReturns:    "aws_security_group",
+				AddressReturns: "aws_security_group.web",
+				BeforeReturns:  map[string]interface{}{"description": "web"},
+				AfterReturns:   map[string]interface{}{"description": "edited"},
+			},
+			expected:       false,
+			expectedOutput: []string{"aws_security_group.web", "(after)", "changed outside of Terraform: description"},
+		},
+		"deleted outside of terraform": {
+			resourcePlan: &planfakes.FakeResourcePlan{
+				TypeReturns:    "aws_instance",
+				AddressReturns: "aws_instance.web",
+				DeleteReturns:  true,
+				BeforeReturns:  map[string]interface{}{"ami": "ami-1"},
+			},
+			expected:       false,
+			expectedOutput: []string{"aws_instance.web", "no matching rule", "deleted outside of Terraform"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			comparer, err := NewDriftComparer(ruleset.UpdateResourceChanges{
+				Strict: true,
+				Resources: []ruleset.UpdateResourceChange{
+					{
+						ResourceIdentifier: ruleset.ResourceIdentifier{Type: "aws_autoscaling_group"},
+						After: &ruleset.ResourceRules{
+							Ignored: []string{"desired_capacity"},
+						},
+					},
+					{
+						ResourceIdentifier: ruleset.ResourceIdentifier{Type: "aws_security_group"},
+						After: &ruleset.ResourceRules{
+							Enforced: map[string]ruleset.EnforceChange{
+								"description": {Value: "web"},
+							},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			output, got := comparer.Diff(tc.resourcePlan)
+			if got != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+			if compared := comparer.Compare(tc.resourcePlan); compared != tc.expected {
+				t.Errorf("Expected compare: %v but got %v", tc.expected, compared)
+			}
+			for _, o := range tc.expectedOutput {
+				if !strings.Contains(output, o) {
+					t.Errorf("Output %s did not contain expected string %s", output, o)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package compare
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/drlau/akashi/pkg/plan"
+	"github.com/drlau/akashi/pkg/ruleset"
+	"github.com/drlau/akashi/pkg/utils"
+)
+
+// OperationComparer compares the resources with a state operation, such as moved resources, against the operation rules
+type OperationComparer struct {
+	Strict bool
+	Rules  []operationRule
+
+	// selects returns true if the resource has the operation
+	selects func(plan.ResourcePlan) bool
+
+	// describe describes the operation of the resource, for example "moved from aws_instance.old"
+	describe func(plan.ResourcePlan) string
+}
+
+type operationRule struct {
+	Selector planSelector
+	Deny     bool
+	From     []string
+
+	fromPatterns []*regexp.Regexp
+}
+
+// NewMovedComparer returns a comparer for resources moved to a new address
+func NewMovedComparer(rules ruleset.OperationChanges) (*OperationComparer, error) {
+	return newOperationComparer(
+		rules,
+		func(r plan.ResourcePlan) bool { return r.GetPreviousAddress() != "" },
+		func(r plan.ResourcePlan) string { return fmt.Sprintf("moved from %s", r.GetPreviousAddress()) },
+	)
+}
+
+// NewImportComparer returns a comparer for imported resources
+func NewImportComparer(rules ruleset.OperationChanges) (*OperationComparer, error) {
+	return newOperationComparer(
+		rules,
+		func(r plan.ResourcePlan) bool { return r.IsImport() },
+		func(r plan.ResourcePlan) string { return "imported" },
+	)
+}
+
+// NewForgetComparer returns a comparer for resources removed from the state without being destroyed
+func NewForgetComparer(rules ruleset.OperationChanges) (*OperationComparer, error) {
+	return newOperationComparer(
+		rules,
+		func(r plan.ResourcePlan) bool { return r.IsForget() },
+		func(r plan.ResourcePlan) string { return "forgotten" },
+	)
+}
+
+func newOperationComparer(rules ruleset.OperationChanges, selects func(plan.ResourcePlan) bool, describe func(plan.ResourcePlan) string) (*OperationComparer, error) {
+	var result []operationRule
+	for _, r := range rules.Resources {
+		selector, err := newPlanSelector(ruleset.PlanRuleSelector{ResourceIdentifier: r.ResourceIdentifier})
+		if err != nil {
+			return nil, err
+		}
+
+		rule := operationRule{
+			Selector: selector,
+			Deny:     r.Deny,
+			From:     r.From,
+		}
+		for _, from := range r.From {
+			id := ruleset.ResourceIdentifier{Address: from}
+			pattern, err := id.AddressPattern()
+			if err != nil {
+				return nil, fmt.Errorf("invalid from address for %s: %v", r.ResourceIdentifier.String(), err)
+			}
+			rule.fromPatterns = append(rule.fromPatterns, pattern)
+		}
+		result = append(result, rule)
+	}
+
+	return &OperationComparer{
+		Strict:   rules.Strict,
+		Rules:    result,
+		selects:  selects,
+		describe: describe,
+	}, nil
+}
+
+func (c *OperationComparer) Compare(rc []plan.ResourcePlan) bool {
+	for _, r := range rc {
+		if !c.selects(r) {
+			continue
+		}
+		if _, ok := c.check(r); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Diff describes every resource with the operation, or returns an empty string if there are none
+func (c *OperationComparer) Diff(rc []plan.ResourcePlan) (string, bool) {
+	var (
+		result []string
+		equal  = true
+	)
+
+	for _, r := range rc {
+		if !c.selects(r) {
+			continue
+		}
+
+		reason, ok := c.check(r)
+		if ok {
+			result = append(result, fmt.Sprintf("%s %s (%s)", utils.Green("✓"), r.GetAddress(), c.describe(r)))
+			continue
+		}
+
+		equal = false
+		result = append(result, fmt.Sprintf("%s %s %s", utils.Red("×"), utils.Red(r.GetAddress()), utils.Red(fmt.Sprintf("(%s)", c.describe(r)))))
+		result = append(result, utils.Red(fmt.Sprintf("  - %s", reason)))
+	}
+
+	return strings.Join(result, "\n"), equal
+}
+
+// check returns the reason the operation of the resource is not allowed by the first matching rule
+func (c *OperationComparer) check(r plan.ResourcePlan) (string, bool) {
+	for _, rule := range c.Rules {
+		if !rule.Selector.matches(r) {
+			continue
+		}
+
+		if rule.Deny {
+			return "denied by rule", false
+		}
+		if len(rule.fromPatterns) > 0 && !rule.matchesFrom(r.GetPreviousAddress()) {
+			return fmt.Sprintf("expected to be moved from one of %v", rule.From), false
+		}
+		return "", true
+	}
+
+	if c.Strict {
+		return "no matching rule", false
+	}
+	return "", true
+}
+
+func (r operationRule) matchesFrom(address string) bool {
+	for _, pattern := range r.fromPatterns {
+		if pattern.MatchString(address) {
+			return true
+		}
+	}
+	return false
+}
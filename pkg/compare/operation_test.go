@@ -0,0 +1,136 @@
+package compare
+
+import (
+	"strings"
+	"testing"
+
+	planfakes "github.com/drlau/akashi/pkg/compare/fakes"
+	"github.com/drlau/akashi/pkg/plan"
+	"github.com/drlau/akashi/pkg/ruleset"
+)
+
+func TestOperationComparerDiff(t *testing.T) {
+	cases := map[string]struct {
+		newComparer       func(ruleset.OperationChanges) (*OperationComparer, error)
+		rules             ruleset.OperationChanges
+		resourcePlan      []plan.ResourcePlan
+		expected          bool
+		expectedOutput    []string
+		unexpectedOutputs []string
+	}{
+		"moved between allowed modules": {
+			newComparer: NewMovedComparer,
+			rules: ruleset.OperationChanges{
+				Resources: []ruleset.OperationChange{
+					{
+						ResourceIdentifier: ruleset.ResourceIdentifier{Address: "module.network.**"},
+						From:               []string{"module.vpc.**"},
+					},
+				},
+			},
+			resourcePlan: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					AddressReturns:         "module.network.aws_subnet.private",
+					PreviousAddressReturns: "module.vpc.aws_subnet.private",
+				},
+				&planfakes.FakeResourcePlan{
+					AddressReturns: "aws_instance.web",
+				},
+			},
+			expected:          true,
+			expectedOutput:    []string{"✓", "module.network.aws_subnet.private (moved from module.vpc.aws_subnet.private)"},
+			unexpectedOutputs: []string{"aws_instance.web"},
+		},
+		"moved from other module": {
+			newComparer: NewMovedComparer,
+			rules: ruleset.OperationChanges{
+				Resources: []ruleset.OperationChange{
+					{
+						ResourceIdentifier: ruleset.ResourceIdentifier{Address: "module.network.**"},
+						From:               []string{"module.vpc.**"},
+					},
+				},
+			},
+			resourcePlan: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					AddressReturns:         "module.network.aws_db_instance.main",
+					PreviousAddressReturns: "module.database.aws_db_instance.main",
+				},
+			},
+			expected:       false,
+			expectedOutput: []string{"×", "module.network.aws_db_instance.main", "expected to be moved from one of [module.vpc.**]"},
+		},
+		"import of unlisted type with strict enabled": {
+			newComparer: NewImportComparer,
+			rules: ruleset.OperationChanges{
+				Strict: true,
+				Resources: []ruleset.OperationChange{
+					{ResourceIdentifier: ruleset.ResourceIdentifier{Type: "aws_s3_bucket"}},
+				},
+			},
+			resourcePlan: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					AddressReturns: "aws_s3_bucket.logs",
+					TypeReturns:    "aws_s3_bucket",
+					ImportReturns:  true,
+				},
+				&planfakes.FakeResourcePlan{
+					AddressReturns: "aws_iam_role.admin",
+					TypeReturns:    "aws_iam_role",
+					ImportReturns:  true,
+				},
+			},
+			expected:       false,
+			expectedOutput: []string{"aws_s3_bucket.logs (imported)", "aws_iam_role.admin", "no matching rule"},
+		},
+		"forget of denied type": {
+			newComparer: NewForgetComparer,
+			rules: ruleset.OperationChanges{
+				Resources: []ruleset.OperationChange{
+					{ResourceIdentifier: ruleset.ResourceIdentifier{Type: "aws_db_instance"}, Deny: true},
+				},
+			},
+			resourcePlan: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					AddressReturns: "aws_db_instance.main",
+					TypeReturns:    "aws_db_instance",
+					ForgetReturns:  true,
+				},
+				&planfakes.FakeResourcePlan{
+					AddressReturns: "aws_instance.web",
+					TypeReturns:    "aws_instance",
+					ForgetReturns:  true,
+				},
+			},
+			expected:       false,
+			expectedOutput: []string{"aws_db_instance.main", "(forgotten)", "denied by rule", "aws_instance.web (forgotten)"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			comparer, err := tc.newComparer(tc.rules)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			output, got := comparer.Diff(tc.resourcePlan)
+			if got != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+			if compared := comparer.Compare(tc.resourcePlan); compared != tc.expected {
+				t.Errorf("Expected compare: %v but got %v", tc.expected, compared)
+			}
+			for _, o := range tc.expectedOutput {
+				if !strings.Contains(output, o) {
+					t.Errorf("Output %s did not contain expected string %s", output, o)
+				}
+			}
+			for _, o := range tc.unexpectedOutputs {
+				if strings.Contains(output, o) {
+					t.Errorf("Output %s contained unexpected string %s", output, o)
+				}
+			}
+		})
+	}
+}
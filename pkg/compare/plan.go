@@ -0,0 +1,238 @@
+package compare
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/drlau/akashi/pkg/plan"
+	"github.com/drlau/akashi/pkg/resource"
+	"github.com/drlau/akashi/pkg/ruleset"
+	"github.com/drlau/akashi/pkg/utils"
+)
+
+// PlanRuleComparer compares the whole plan against the plan rules
+type PlanRuleComparer struct {
+	Rules []planRule
+}
+
+type planRule struct {
+	Name     string
+	Resource planSelector
+	Requires planSelector
+	Match    map[string]string
+}
+
+type planSelector struct {
+	ruleset.PlanRuleSelector
+
+	Pattern *regexp.Regexp
+}
+
+func NewPlanRuleComparer(rules []ruleset.PlanRule) (*PlanRuleComparer, error) {
+	var result []planRule
+	for _, r := range rules {
+		resourceSelector, err := newPlanSelector(r.Resource)
+		if err != nil {
+			return nil, fmt.Errorf("plan rule %s: %v", r.String(), err)
+		}
+		requiresSelector, err := newPlanSelector(r.Requires)
+		if err != nil {
+			return nil, fmt.Errorf("plan rule %s: %v", r.String(), err)
+		}
+
+		result = append(result, planRule{
+			Name:     r.String(),
+			Resource: resourceSelector,
+			Requires: requiresSelector,
+			Match:    r.Match,
+		})
+	}
+
+	return &PlanRuleComparer{
+		Rules: result,
+	}, nil
+}
+
+func newPlanSelector(s ruleset.PlanRuleSelector) (planSelector, error) {
+	for _, action := range s.Actions {
+		switch action {
+		case ruleset.ActionCreate, ruleset.ActionDestroy, ruleset.ActionUpdate, ruleset.ActionReplace, ruleset.ActionRead:
+		default:
+			return planSelector{}, fmt.Errorf("unknown action %q", action)
+		}
+	}
+
+	selector := planSelector{
+		PlanRuleSelector: s,
+	}
+	if s.IsAddressPattern() {
+		pattern, err := s.AddressPattern()
+		if err != nil {
+			return planSelector{}, fmt.Errorf("invalid address pattern for %s: %v", s.String(), err)
+		}
+		selector.Pattern = pattern
+	}
+
+	return selector, nil
+}
+
+func (c *PlanRuleComparer) Compare(rc []plan.ResourcePlan) bool {
+	for _, rule := range c.Rules {
+		if len(rule.failures(rc)) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *PlanRuleComparer) Diff(rc []plan.ResourcePlan) (string, bool) {
+	var (
+		result []string
+		equal  = true
+	)
+
+	for _, rule := range c.Rules {
+		failures := rule.failures(rc)
+		if len(failures) == 0 {
+			result = append(result, fmt.Sprintf("%s (plan rule: %s)", utils.Green("✓"), rule.Name))
+			continue
+		}
+
+		equal = false
+		for _, f := range failures {
+			result = append(result, fmt.Sprintf("%s %s %s", utils.Red("×"), utils.Red(f.GetAddress()), utils.Red(fmt.Sprintf("(plan rule: %s)", rule.Name))))
+			result = append(result, utils.Red(fmt.Sprintf("  - missing %s%s", rule.Requires.String(), rule.describeMatch(f))))
+		}
+	}
+
+	return strings.Join(result, "\n"), equal
+}
+
+// failures returns the resources selected by the rule without a required resource
+func (r planRule) failures(rc []plan.ResourcePlan) []plan.ResourcePlan {
+	var result []plan.ResourcePlan
+	for _, res := range rc {
+		if !r.Resource.matches(res) {
+			continue
+		}
+
+		found := false
+		for _, required := range rc {
+			if r.Requires.matches(required) && r.matchValues(res, required) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, res)
+		}
+	}
+
+	return result
+}
+
+// matchValues returns true if every matched argument of the required resource equals the argument of the resource
+// Computed arguments are unknown until apply, so they match any value
+func (r planRule) matchValues(res, required plan.ResourcePlan) bool {
+	values, requiredValues := planValues(res), planValues(required)
+	for requiredKey, key := range r.Match {
+		if resource.IsComputed(res.GetComputed(), key) || resource.IsComputed(required.GetComputed(), requiredKey) {
+			continue
+		}
+
+		v, ok := resource.LookupPath(values, key)
+		if !ok {
+			return false
+		}
+		requiredV, ok := resource.LookupPath(requiredValues, requiredKey)
+		if !ok || !reflect.DeepEqual(v, requiredV) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// describeMatch describes the arguments the required resource must have for the resource
+func (r planRule) describeMatch(res plan.ResourcePlan) string {
+	if len(r.Match) == 0 {
+		return ""
+	}
+
+	var keys []string
+	for k := range r.Match {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	values := planValues(res)
+	var result []string
+	for _, k := range keys {
+		v, ok := resource.LookupPath(values, r.Match[k])
+		if !ok {
+			v = "<absent>"
+		}
+		result = append(result, fmt.Sprintf("%s = %v", k, v))
+	}
+	return fmt.Sprintf(" with %s", strings.Join(result, ", "))
+}
+
+// planValues returns the values of the resource after the change, or before the change for destroyed resources
+func planValues(r plan.ResourcePlan) map[string]interface{} {
+	if r.IsDelete() {
+		return r.GetBefore()
+	}
+	return r.GetAfter()
+}
+
+// matches returns true if the resource has one of the actions and matches the resource identifier
+func (s planSelector) matches(r plan.ResourcePlan) bool {
+	if !s.matchesAction(r) {
+		return false
+	}
+	if s.Pattern != nil {
+		return s.Pattern.MatchString(r.GetAddress())
+	}
+
+	if s.Type != "" && s.Type != r.GetType() {
+		return false
+	}
+	if s.Name != "" && s.Name != r.GetName() {
+		return false
+	}
+	if s.Index != nil && s.Index != ruleset.IndexWildcard && ruleset.FormatIndex(s.Index) != ruleset.FormatIndex(r.GetIndex()) {
+		return false
+	}
+	if s.Module != "" {
+		for _, module := range moduleCandidates(r.GetModuleAddress()) {
+			if module == s.Module {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+func (s planSelector) matchesAction(r plan.ResourcePlan) bool {
+	if len(s.Actions) == 0 {
+		return true
+	}
+
+	for _, action := range s.Actions {
+		switch {
+		case action == ruleset.ActionCreate && r.IsCreate(),
+			action == ruleset.ActionDestroy && r.IsDelete(),
+			action == ruleset.ActionUpdate && r.IsUpdate(),
+			action == ruleset.ActionReplace && r.IsReplace(),
+			action == ruleset.ActionRead && r.IsRead():
+			return true
+		}
+	}
+	return false
+}
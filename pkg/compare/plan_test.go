@@ -0,0 +1,177 @@
+package compare
+
+import (
+	"strings"
+	"testing"
+
+	planfakes "github.com/drlau/akashi/pkg/compare/fakes"
+	"github.com/drlau/akashi/pkg/plan"
+	"github.com/drlau/akashi/pkg/ruleset"
+)
+
+func TestPlanRuleComparerDiff(t *testing.T) {
+	bucketRule := ruleset.PlanRule{
+		Resource: ruleset.PlanRuleSelector{
+			ResourceIdentifier: ruleset.ResourceIdentifier{
+				Type: "aws_s3_bucket",
+			},
+			Actions: []string{ruleset.ActionCreate},
+		},
+		Requires: ruleset.PlanRuleSelector{
+			ResourceIdentifier: ruleset.ResourceIdentifier{
+				Type: "aws_s3_bucket_public_access_block",
+			},
+			Actions: []string{ruleset.ActionCreate},
+		},
+		Match: map[string]string{
+			"bucket": "bucket",
+		},
+	}
+
+	cases := map[string]struct {
+		rules          []ruleset.PlanRule
+		resourcePlan   []plan.ResourcePlan
+		expected       bool
+		expectedOutput []string
+	}{
+		"required resource with matching argument": {
+			rules: []ruleset.PlanRule{bucketRule},
+			resourcePlan: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					AddressReturns: "aws_s3_bucket.logs",
+					TypeReturns:    "aws_s3_bucket",
+					CreateReturns:  true,
+					AfterReturns:   map[string]interface{}{"bucket": "logs"},
+				},
+				&planfakes.FakeResourcePlan{
+					AddressReturns: "aws_s3_bucket_public_access_block.logs",
+					TypeReturns:    "aws_s3_bucket_public_access_block",
+					CreateReturns:  true,
+					AfterReturns:   map[string]interface{}{"bucket": "logs"},
+				},
+			},
+			expected:       true,
+			expectedOutput: []string{"✓", "(plan rule: aws_s3_bucket [create] requires aws_s3_bucket_public_access_block [create])"},
+		},
+		"required resource with different argument": {
+			rules: []ruleset.PlanRule{bucketRule},
+			resourcePlan: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					AddressReturns: "aws_s3_bucket.logs",
+					TypeReturns:    "aws_s3_bucket",
+					CreateReturns:  true,
+					AfterReturns:   map[string]interface{}{"bucket": "logs"},
+				},
+				&planfakes.FakeResourcePlan{
+					AddressReturns: "aws_s3_bucket_public_access_block.data",
+					TypeReturns:    "aws_s3_bucket_public_access_block",
+					CreateReturns:  true,
+					AfterReturns:   map[string]interface{}{"bucket": "data"},
+				},
+			},
+			expected:       false,
+			expectedOutput: []string{"×", "aws_s3_bucket.logs", "missing aws_s3_bucket_public_access_block [create] with bucket = logs"},
+		},
+		"required resource with computed argument": {
+			rules: []ruleset.PlanRule{bucketRule},
+			resourcePlan: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					AddressReturns: "aws_s3_bucket.logs",
+					TypeReturns:    "aws_s3_bucket",
+					CreateReturns:  true,
+					AfterReturns:   map[string]interface{}{"bucket": "logs"},
+				},
+				&planfakes.FakeResourcePlan{
+					AddressReturns:  "aws_s3_bucket_public_access_block.logs",
+					TypeReturns:     "aws_s3_bucket_public_access_block",
+					CreateReturns:   true,
+					ComputedReturns: map[string]interface{}{"bucket": true},
+				},
+			},
+			expected:       true,
+			expectedOutput: []string{"✓"},
+		},
+		"required resource with different action": {
+			rules: []ruleset.PlanRule{
+				{
+					Name: "iam members have audit configs",
+					Resource: ruleset.PlanRuleSelector{
+						ResourceIdentifier: ruleset.ResourceIdentifier{
+							Type: "google_project_iam_member",
+						},
+					},
+					Requires: ruleset.PlanRuleSelector{
+						ResourceIdentifier: ruleset.ResourceIdentifier{
+							Type: "google_project_iam_audit_config",
+						},
+						Actions: []string{ruleset.ActionCreate},
+					},
+				},
+			},
+			resourcePlan: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					AddressReturns: "google_project_iam_member.viewer",
+					TypeReturns:    "google_project_iam_member",
+					CreateReturns:  true,
+				},
+				&planfakes.FakeResourcePlan{
+					AddressReturns: "google_project_iam_audit_config.all",
+					TypeReturns:    "google_project_iam_audit_config",
+					DeleteReturns:  true,
+				},
+			},
+			expected:       false,
+			expectedOutput: []string{"×", "google_project_iam_member.viewer", "(plan rule: iam members have audit configs)"},
+		},
+		"no selected resources": {
+			rules: []ruleset.PlanRule{bucketRule},
+			resourcePlan: []plan.ResourcePlan{
+				&planfakes.FakeResourcePlan{
+					AddressReturns: "aws_s3_bucket.logs",
+					TypeReturns:    "aws_s3_bucket",
+					DeleteReturns:  true,
+				},
+			},
+			expected:       true,
+			expectedOutput: []string{"✓"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			comparer, err := NewPlanRuleComparer(tc.rules)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			output, got := comparer.Diff(tc.resourcePlan)
+			if got != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+			if compared := comparer.Compare(tc.resourcePlan); compared != tc.expected {
+				t.Errorf("Expected compare: %v but got %v", tc.expected, compared)
+			}
+			for _, o := range tc.expectedOutput {
+				if !strings.Contains(output, o) {
+					t.Errorf("Output %s did not contain expected string %s", output, o)
+				}
+			}
+		})
+	}
+}
+
+func TestNewPlanRuleComparerInvalidAction(t *testing.T) {
+	_, err := NewPlanRuleComparer([]ruleset.PlanRule{
+		{
+			Resource: ruleset.PlanRuleSelector{
+				ResourceIdentifier: ruleset.ResourceIdentifier{
+					Type: "aws_s3_bucket",
+				},
+				Actions: []string{"created"},
+			},
+		},
+	})
+	if err == nil {
+		t.Errorf("Expected an error for an unknown action")
+	}
+}
@@ -11,8 +11,10 @@ import (
 )
 
 type UpdateComparer struct {
-	Strict bool
+	Strict   bool
+	MatchAll bool
 
+	AddressResources  []addressResource[updateResource]
 	NameResources     map[string]updateResource
 	TypeResources     map[string]updateResource
 	NameTypeResources map[string]updateResource
@@ -21,17 +23,29 @@ type UpdateComparer struct {
 type updateResource struct {
 	Before Resource
 	After  Resource
+
+	// AllowedReplaceTriggers are the arguments allowed to force a replacement
+	// If nil, replacements are not checked
+	AllowedReplaceTriggers []string
 }
 
-func NewUpdateComparer(ruleset ruleset.UpdateResourceChanges) *UpdateComparer {
+func NewUpdateComparer(ruleset ruleset.UpdateResourceChanges) (*UpdateComparer, error) {
+	matchAll, err := newMatchAll(ruleset.MatchMode)
+	if err != nil {
+		return nil, err
+	}
+
 	defaultOptions := resource.NewCompareOptions(ruleset.Default)
+	var addressResources []addressResource[updateResource]
 	nameTypeResources := make(map[string]updateResource)
 	typeResources := make(map[string]updateResource)
 	nameResources := make(map[string]updateResource)
 
 	// Iterate over all the resources
 	for _, r := range ruleset.Resources {
-		var ur updateResource
+		ur := updateResource{
+			AllowedReplaceTriggers: r.AllowedReplaceTriggers,
+		}
 		if r.Before != nil {
 			ur.Before = resource.NewResourceFromConfig(r.ResourceIdentifier, *r.Before, &r.CompareOptions, defaultOptions)
 		}
@@ -39,25 +53,34 @@ func NewUpdateComparer(ruleset ruleset.UpdateResourceChanges) *UpdateComparer {
 			ur.After = resource.NewResourceFromConfig(r.ResourceIdentifier, *r.After, &r.CompareOptions, defaultOptions)
 		}
 
-		if r.Name != "" && r.Type != "" {
+		if r.IsAddressPattern() {
+			ar, err := newAddressResource(r.ResourceIdentifier, ur)
+			if err != nil {
+				return nil, err
+			}
+			addressResources = append(addressResources, ar)
+		} else if r.Name != "" && r.Type != "" {
 			// format name and type key
-			nameTypeResources[fmt.Sprintf("%s.%s", r.Type, r.Name)] = ur
+			nameTypeResources[constructRuleKey(fmt.Sprintf("%s.%s", r.Type, r.Name), r.Module, r.Index)] = ur
 		} else if r.Name != "" {
-			nameResources[r.Name] = ur
+			nameResources[constructRuleKey(r.Name, r.Module, r.Index)] = ur
 		} else if r.Type != "" {
-			typeResources[r.Type] = ur
+			typeResources[constructRuleKey(r.Type, r.Module, r.Index)] = ur
 		}
 	}
+	sortAddressResources(addressResources)
+
 	return &UpdateComparer{
 		Strict:            ruleset.Strict,
+		MatchAll:          matchAll,
+		AddressResources:  addressResources,
 		NameResources:     nameResources,
 		TypeResources:     typeResources,
 		NameTypeResources: nameTypeResources,
-	}
+	}, nil
 }
 
 func (c *UpdateComparer) Compare(r plan.ResourcePlan) bool {
-	nameType := constructNameTypeKey(r)
 	beforeChanges := resource.ResourceValues{
 		Values:        r.GetBefore(),
 		ChangedValues: r.GetBeforeChangedOnly(),
@@ -66,43 +89,30 @@ func (c *UpdateComparer) Compare(r plan.ResourcePlan) bool {
 		Values:        r.GetAfter(),
 		ChangedValues: r.GetAfterChangedOnly(),
 		Computed:      r.GetComputed(),
+		Previous:      r.GetBefore(),
 	}
 
-	var (
-		beforeOk = true
-		afterOk  = true
-	)
-	if ro, ok := c.NameTypeResources[nameType]; ok {
-		if ro.Before != nil {
-			beforeOk = ro.Before.Compare(beforeChanges)
-		}
-		if ro.After != nil {
-			afterOk = ro.After.Compare(afterChanges)
-		}
-		return beforeOk && afterOk
-	} else if ro, ok := c.NameResources[r.GetName()]; ok {
-		if ro.Before != nil {
-			beforeOk = ro.Before.Compare(beforeChanges)
-		}
-		if ro.After != nil {
-			afterOk = ro.After.Compare(afterChanges)
+	matches := c.match(r)
+	if len(matches) == 0 {
+		return !c.Strict
+	}
+
+	for _, m := range matches {
+		if m.Resource.Before != nil && !m.Resource.Before.Compare(beforeChanges) {
+			return false
 		}
-		return beforeOk && afterOk
-	} else if ro, ok := c.TypeResources[r.GetType()]; ok {
-		if ro.Before != nil {
-			beforeOk = ro.Before.Compare(beforeChanges)
+		if m.Resource.After != nil && !m.Resource.After.Compare(afterChanges) {
+			return false
 		}
-		if ro.After != nil {
-			afterOk = ro.After.Compare(afterChanges)
+		if len(m.Resource.disallowedReplaceTriggers(r)) > 0 {
+			return false
 		}
-		return beforeOk && afterOk
 	}
 
-	return !c.Strict
+	return true
 }
 
 func (c *UpdateComparer) Diff(r plan.ResourcePlan) (string, bool) {
-	nameType := constructNameTypeKey(r)
 	// TODO: handle IgnoreNoOp
 	beforeChanges := resource.ResourceValues{
 		Values:        r.GetBefore(),
@@ -112,16 +122,11 @@ func (c *UpdateComparer) Diff(r plan.ResourcePlan) (string, bool) {
 		Values:        r.GetAfter(),
 		ChangedValues: r.GetAfterChangedOnly(),
 		Computed:      r.GetComputed(),
+		Previous:      r.GetBefore(),
 	}
 
-	var ur updateResource
-	if rs, ok := c.NameTypeResources[nameType]; ok {
-		ur = rs
-	} else if rs, ok := c.NameResources[r.GetName()]; ok {
-		ur = rs
-	} else if rs, ok := c.TypeResources[r.GetType()]; ok {
-		ur = rs
-	} else {
+	matches := c.match(r)
+	if len(matches) == 0 {
 		if c.Strict {
 			return fmt.Sprintf("%s %s (no matching rule)", utils.Red("×"), r.GetAddress()), false
 		}
@@ -134,19 +139,30 @@ func (c *UpdateComparer) Diff(r plan.ResourcePlan) (string, bool) {
 		equal  = true
 	)
 
-	if ur.Before != nil {
-		diff := ur.Before.Diff(beforeChanges)
-		if diff != "" {
-			equal = false
-			result.WriteString(fmt.Sprintf("%s %s %s\n%s\n", utils.Red("×"), utils.Red(r.GetAddress()), utils.Red("(before)"), diff))
+	for _, m := range matches {
+		address := formatFailedAddress(r.GetAddress(), m.Rule, c.MatchAll)
+		if m.Resource.Before != nil {
+			diff := m.Resource.Before.Diff(beforeChanges)
+			if diff != "" {
+				equal = false
+				result.WriteString(fmt.Sprintf("%s %s\n%s\n", address, utils.Red("(before)"), diff))
+			}
 		}
-	}
 
-	if ur.After != nil {
-		diff := ur.After.Diff(afterChanges)
-		if diff != "" {
+		if m.Resource.After != nil {
+			diff := m.Resource.After.Diff(afterChanges)
+			if diff != "" {
+				equal = false
+				result.WriteString(fmt.Sprintf("%s %s\n%s\n", address, utils.Red("(after)"), diff))
+			}
+		}
+
+		if disallowed := m.Resource.disallowedReplaceTriggers(r); len(disallowed) > 0 {
 			equal = false
-			result.WriteString(fmt.Sprintf("%s %s %s\n%s\n", utils.Red("×"), utils.Red(r.GetAddress()), utils.Red("(after)"), diff))
+			result.WriteString(fmt.Sprintf("%s %s\n%s", address, utils.Red("(replace triggers)"), utils.Red("Replace triggers not allowed:\n")))
+			for _, path := range disallowed {
+				result.WriteString(utils.Red(fmt.Sprintf("  - %s\n", path)))
+			}
 		}
 	}
 
@@ -156,3 +172,47 @@ func (c *UpdateComparer) Diff(r plan.ResourcePlan) (string, bool) {
 
 	return strings.TrimSuffix(result.String(), "\n"), equal
 }
+
+// match returns the rules for the resource
+// Exact addresses are checked first, then name and type, then name, then type, then the other address patterns
+func (c *UpdateComparer) match(r plan.ResourcePlan) []matchedResource[updateResource] {
+	module := r.GetModuleAddress()
+	index := r.GetIndex()
+
+	return matchResources(
+		c.MatchAll,
+		lookupAddressResources(c.AddressResources, r.GetAddress(), true),
+		lookupResources(c.NameTypeResources, constructNameTypeKey(r), module, index),
+		lookupResources(c.NameResources, r.GetName(), module, index),
+		lookupResources(c.TypeResources, r.GetType(), module, index),
+		lookupAddressResources(c.AddressResources, r.GetAddress(), false),
+	)
+}
+
+// disallowedReplaceTriggers returns the arguments that forced the replacement of the resource and are not allowed
+// A replacement without a known argument, such as a tainted resource, is reported as unknown
+func (ur updateResource) disallowedReplaceTriggers(r plan.ResourcePlan) []string {
+	if ur.AllowedReplaceTriggers == nil || !r.IsReplace() {
+		return nil
+	}
+
+	paths := r.GetReplacePaths()
+	if len(paths) == 0 {
+		return []string{"<unknown>"}
+	}
+
+	var result []string
+	for _, path := range paths {
+		allowed := false
+		for _, trigger := range ur.AllowedReplaceTriggers {
+			if resource.MatchPathPrefix(trigger, path) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			result = append(result, path)
+		}
+	}
+	return result
+}
@@ -7,6 +7,7 @@ import (
 	comparefakes "github.com/drlau/akashi/pkg/compare/fakes"
 	planfakes "github.com/drlau/akashi/pkg/compare/fakes"
 	"github.com/drlau/akashi/pkg/plan"
+	"github.com/drlau/akashi/pkg/ruleset"
 )
 
 func TestUpdateCompare(t *testing.T) {
@@ -185,6 +186,67 @@ func TestUpdateCompare(t *testing.T) {
 			},
 			expected: true,
 		},
+		"prioritizes matching index resource": {
+			comparer: &UpdateComparer{
+				NameTypeResources: map[string]updateResource{
+					"type.name[\"blue\"]": {
+						After: &comparefakes.FakeResource{
+							CompareReturns: true,
+						},
+					},
+					"type.name": {
+						After: &comparefakes.FakeResource{
+							CompareReturns: false,
+						},
+					},
+				},
+			},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				NameReturns:  "name",
+				TypeReturns:  "type",
+				IndexReturns: "blue",
+			},
+			expected: true,
+		},
+		"matching wildcard index resource": {
+			comparer: &UpdateComparer{
+				NameTypeResources: map[string]updateResource{
+					"type.name[*]": {
+						After: &comparefakes.FakeResource{
+							CompareReturns: false,
+						},
+					},
+				},
+			},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				NameReturns:  "name",
+				TypeReturns:  "type",
+				IndexReturns: "green",
+			},
+			expected: false,
+		},
+		"prioritizes matching module resource": {
+			comparer: &UpdateComparer{
+				NameTypeResources: map[string]updateResource{
+					"module.network.module.subnets.type.name": {
+						After: &comparefakes.FakeResource{
+							CompareReturns: true,
+						},
+					},
+					"module.network.*.type.name": {
+						After: &comparefakes.FakeResource{
+							CompareReturns: false,
+						},
+					},
+				},
+			},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				NameReturns:   "name",
+				TypeReturns:   "type",
+				ModuleReturns: "module.network.module.subnets",
+			},
+			expected: true,
+		},
 		"no matching resource": {
 			comparer: &UpdateComparer{},
 			resourcePlan: &planfakes.FakeResourcePlan{
@@ -409,6 +471,32 @@ func TestUpdateDiff(t *testing.T) {
 			expected:       true,
 			expectedOutput: []string{""},
 		},
+		"match all reports failures by resource": {
+			comparer: &UpdateComparer{
+				MatchAll: true,
+				NameTypeResources: map[string]updateResource{
+					"type.name": {
+						Before: &comparefakes.FakeResource{
+							DiffReturns: "failedBefore",
+						},
+					},
+				},
+				TypeResources: map[string]updateResource{
+					"type": {
+						After: &comparefakes.FakeResource{
+							DiffReturns: "failedAfter",
+						},
+					},
+				},
+			},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				AddressReturns: "address",
+				NameReturns:    "name",
+				TypeReturns:    "type",
+			},
+			expected:       false,
+			expectedOutput: []string{"(rule: type.name)", "(before)", "failedBefore", "(rule: type)", "(after)", "failedAfter"},
+		},
 		"no matching resource": {
 			comparer: &UpdateComparer{},
 			resourcePlan: &planfakes.FakeResourcePlan{
@@ -447,3 +535,162 @@ func TestUpdateDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateCompareRelational(t *testing.T) {
+	maxDelta := float64(2)
+	cases := map[string]struct {
+		enforced map[string]ruleset.EnforceChange
+		before   map[string]interface{}
+		after    map[string]interface{}
+		expected bool
+	}{
+		"increase only with larger value": {
+			enforced: map[string]ruleset.EnforceChange{
+				"disk_size_gb": {IncreaseOnly: true},
+			},
+			before:   map[string]interface{}{"disk_size_gb": float64(100)},
+			after:    map[string]interface{}{"disk_size_gb": float64(200)},
+			expected: true,
+		},
+		"increase only with smaller value": {
+			enforced: map[string]ruleset.EnforceChange{
+				"disk_size_gb": {IncreaseOnly: true},
+			},
+			before:   map[string]interface{}{"disk_size_gb": float64(100)},
+			after:    map[string]interface{}{"disk_size_gb": float64(50)},
+			expected: false,
+		},
+		"max delta with values parsed from text": {
+			enforced: map[string]ruleset.EnforceChange{
+				"node_count": {MaxDelta: &maxDelta},
+			},
+			before:   map[string]interface{}{"node_count": "3"},
+			after:    map[string]interface{}{"node_count": "6"},
+			expected: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			comparer, err := NewUpdateComparer(ruleset.UpdateResourceChanges{
+				Resources: []ruleset.UpdateResourceChange{
+					{
+						ResourceIdentifier: ruleset.ResourceIdentifier{
+							Type: "type",
+						},
+						After: &ruleset.ResourceRules{
+							Enforced: tc.enforced,
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := comparer.Compare(&planfakes.FakeResourcePlan{
+				TypeReturns:   "type",
+				BeforeReturns: tc.before,
+				AfterReturns:  tc.after,
+			})
+			if got != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestUpdateDiffReplaceTriggers(t *testing.T) {
+	cases := map[string]struct {
+		allowed        []string
+		resourcePlan   *planfakes.FakeResourcePlan
+		expected       bool
+		expectedOutput []string
+	}{
+		"replaced by allowed trigger": {
+			allowed: []string{"ami", "root_block_device"},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				ReplaceReturns:      true,
+				ReplacePathsReturns: []string{"ami", "root_block_device[0].volume_type"},
+			},
+			expected:       true,
+			expectedOutput: []string{"✓"},
+		},
+		"replaced by other trigger": {
+			allowed: []string{"ami"},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				ReplaceReturns:      true,
+				ReplacePathsReturns: []string{"ami", "subnet_id"},
+			},
+			expected:       false,
+			expectedOutput: []string{"(replace triggers)", "- subnet_id"},
+		},
+		"replaced without known trigger": {
+			allowed: []string{"ami"},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				ReplaceReturns: true,
+			},
+			expected:       false,
+			expectedOutput: []string{"- <unknown>"},
+		},
+		"replaced without allowed triggers": {
+			allowed: []string{},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				ReplaceReturns:      true,
+				ReplacePathsReturns: []string{"engine_version"},
+			},
+			expected:       false,
+			expectedOutput: []string{"- engine_version"},
+		},
+		"updated in place": {
+			allowed: []string{"ami"},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				UpdateReturns: true,
+			},
+			expected:       true,
+			expectedOutput: []string{"✓"},
+		},
+		"wildcard trigger": {
+			allowed: []string{"ebs_block_device.*.volume_size"},
+			resourcePlan: &planfakes.FakeResourcePlan{
+				ReplaceReturns:      true,
+				ReplacePathsReturns: []string{"ebs_block_device[1].volume_size", "ebs_block_device[1].device_name"},
+			},
+			expected:       false,
+			expectedOutput: []string{"- ebs_block_device[1].device_name"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			comparer, err := NewUpdateComparer(ruleset.UpdateResourceChanges{
+				Resources: []ruleset.UpdateResourceChange{
+					{
+						ResourceIdentifier: ruleset.ResourceIdentifier{
+							Type: "aws_instance",
+						},
+						AllowedReplaceTriggers: tc.allowed,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			tc.resourcePlan.TypeReturns = "aws_instance"
+			tc.resourcePlan.AddressReturns = "aws_instance.web"
+			output, got := comparer.Diff(tc.resourcePlan)
+			if got != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+			if compared := comparer.Compare(tc.resourcePlan); compared != tc.expected {
+				t.Errorf("Expected compare: %v but got %v", tc.expected, compared)
+			}
+			for _, o := range tc.expectedOutput {
+				if !strings.Contains(output, o) {
+					t.Errorf("Output %s did not contain expected string %s", output, o)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package plan
+
+import (
+	"strings"
+)
+
+// SplitAddress splits a resource or module address on "." while keeping
+// index keys intact, even if they contain a "."
+// Example: module.vpc["a.b"].aws_subnet.private -> [module vpc["a.b"] aws_subnet private]
+func SplitAddress(address string) []string {
+	var (
+		result   []string
+		current  strings.Builder
+		inIndex  bool
+		inQuotes bool
+	)
+
+	for _, c := range address {
+		switch {
+		case c == '"' && inIndex:
+			inQuotes = !inQuotes
+		case c == '[' && !inQuotes:
+			inIndex = true
+		case c == ']' && !inQuotes:
+			inIndex = false
+		case c == '.' && !inIndex:
+			result = append(result, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(c)
+	}
+
+	return append(result, current.String())
+}
+
+// moduleAddressFromAddress returns the module portion of a resource address
+// Example: module.network.module.subnets.aws_subnet.private -> module.network.module.subnets
+func moduleAddressFromAddress(address string) string {
+	var modules []string
+
+	values := SplitAddress(address)
+	for i := 0; i+1 < len(values) && values[i] == "module"; i += 2 {
+		modules = append(modules, "module."+values[i+1])
+	}
+
+	return strings.Join(modules, ".")
+}
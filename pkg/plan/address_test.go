@@ -0,0 +1,72 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSplitAddress(t *testing.T) {
+	cases := map[string]struct {
+		address  string
+		expected []string
+	}{
+		"resource": {
+			address:  "type.name",
+			expected: []string{"type", "name"},
+		},
+		"resource with index": {
+			address:  `type.name["blue"]`,
+			expected: []string{"type", `name["blue"]`},
+		},
+		"nested module": {
+			address:  "module.network.module.subnets.type.name[0]",
+			expected: []string{"module", "network", "module", "subnets", "type", "name[0]"},
+		},
+		"module with index containing separator": {
+			address:  `module.vpc["a.b"].type.name`,
+			expected: []string{"module", `vpc["a.b"]`, "type", "name"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := SplitAddress(tc.address)
+			if diff := cmp.Diff(got, tc.expected); diff != "" {
+				t.Errorf("(-got, +expected)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestModuleAddressFromAddress(t *testing.T) {
+	cases := map[string]struct {
+		address  string
+		expected string
+	}{
+		"root module": {
+			address:  "type.name",
+			expected: "",
+		},
+		"module": {
+			address:  "module.vpc.type.name",
+			expected: "module.vpc",
+		},
+		"nested module": {
+			address:  "module.network.module.subnets.type.name[0]",
+			expected: "module.network.module.subnets",
+		},
+		"module with index": {
+			address:  `module.vpc["a.b"].data.type.name`,
+			expected: `module.vpc["a.b"]`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := moduleAddressFromAddress(tc.address); got != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
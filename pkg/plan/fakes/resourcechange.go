@@ -4,13 +4,22 @@ type FakeResourcePlan struct {
 	AddressReturns  string
 	NameReturns     string
 	TypeReturns     string
+	IndexReturns    interface{}
+	ModuleReturns   string
 	CreateReturns   bool
 	DeleteReturns   bool
 	NoOpReturns     bool
+	ReadReturns     bool
 	UpdateReturns   bool
+	ReplaceReturns  bool
 	BeforeReturns   map[string]interface{}
 	AfterReturns    map[string]interface{}
 	ComputedReturns map[string]interface{}
+
+	ReplacePathsReturns    []string
+	PreviousAddressReturns string
+	ImportReturns          bool
+	ForgetReturns          bool
 }
 
 func (r *FakeResourcePlan) GetAddress() string {
@@ -25,6 +34,14 @@ func (r *FakeResourcePlan) GetType() string {
 	return r.TypeReturns
 }
 
+func (r *FakeResourcePlan) GetIndex() interface{} {
+	return r.IndexReturns
+}
+
+func (r *FakeResourcePlan) GetModuleAddress() string {
+	return r.ModuleReturns
+}
+
 func (r *FakeResourcePlan) IsCreate() bool {
 	return r.CreateReturns
 }
@@ -37,10 +54,34 @@ func (r *FakeResourcePlan) IsNoOp() bool {
 	return r.NoOpReturns
 }
 
+func (r *FakeResourcePlan) IsRead() bool {
+	return r.ReadReturns
+}
+
 func (r *FakeResourcePlan) IsUpdate() bool {
 	return r.UpdateReturns
 }
 
+func (r *FakeResourcePlan) IsReplace() bool {
+	return r.ReplaceReturns
+}
+
+func (r *FakeResourcePlan) GetReplacePaths() []string {
+	return r.ReplacePathsReturns
+}
+
+func (r *FakeResourcePlan) GetPreviousAddress() string {
+	return r.PreviousAddressReturns
+}
+
+func (r *FakeResourcePlan) IsImport() bool {
+	return r.ImportReturns
+}
+
+func (r *FakeResourcePlan) IsForget() bool {
+	return r.ForgetReturns
+}
+
 func (r *FakeResourcePlan) GetBefore() map[string]interface{} {
 	return r.BeforeReturns
 }
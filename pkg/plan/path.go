@@ -0,0 +1,88 @@
+package plan
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/drlau/tfplanparse"
+)
+
+// attributeChange is the attribute change of a tfplanparse resource change
+type attributeChange interface {
+	GetName() string
+	GetUpdateType() tfplanparse.UpdateType
+}
+
+// formatPath formats the steps of a terraform-json path as a dotted path
+// Example: [ebs_block_device 0 volume_size] -> ebs_block_device[0].volume_size
+func formatPath(steps []interface{}) string {
+	var result strings.Builder
+	for _, step := range steps {
+		switch s := step.(type) {
+		case float64:
+			result.WriteString(fmt.Sprintf("[%d]", int(s)))
+		default:
+			if result.Len() > 0 {
+				result.WriteString(".")
+			}
+			result.WriteString(fmt.Sprint(s))
+		}
+	}
+	return result.String()
+}
+
+// replacePaths returns the paths of the attributes that force a replacement, including nested attributes
+func replacePaths(prefix string, a attributeChange) []string {
+	path := a.GetName()
+	if prefix != "" {
+		path = fmt.Sprintf("%s.%s", prefix, a.GetName())
+	}
+
+	switch casted := a.(type) {
+	case *tfplanparse.MapAttributeChange:
+		var result []string
+		for _, nested := range casted.AttributeChanges {
+			result = append(result, replacePaths(path, nested)...)
+		}
+		return result
+	case *tfplanparse.ArrayAttributeChange:
+		for _, nested := range casted.AttributeChanges {
+			if len(replacePaths("", nested)) > 0 {
+				return []string{path}
+			}
+		}
+		return nil
+	}
+
+	if a.GetUpdateType() == tfplanparse.ForceReplaceResource {
+		return []string{path}
+	}
+	return nil
+}
+
+// changedValues returns the values of b that differ from a, keeping only the changed keys of nested maps
+// Keys of a that were removed from b are returned as nil
+func changedValues(a, b map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			result[k] = nil
+		}
+	}
+	for k, v := range b {
+		old, ok := a[k]
+		if ok && reflect.DeepEqual(old, v) {
+			continue
+		}
+
+		oldMap, oldOk := old.(map[string]interface{})
+		newMap, newOk := v.(map[string]interface{})
+		if oldOk && newOk {
+			result[k] = changedValues(oldMap, newMap)
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
@@ -13,7 +13,20 @@ type ResourcePlan interface {
 	IsCreate() bool
 	IsDelete() bool
 	IsNoOp() bool
+	// IsRead returns true if the resource is a data source read during apply
+	IsRead() bool
 	IsUpdate() bool
+	// IsReplace returns true if the resource is destroyed and created again
+	// Replaced resources are not creates, deletes or updates
+	IsReplace() bool
+	// GetReplacePaths returns the paths of the arguments that forced the replacement
+	GetReplacePaths() []string
+	// GetPreviousAddress returns the address the resource was moved from, or an empty string if it was not moved
+	GetPreviousAddress() string
+	// IsImport returns true if the resource is imported
+	IsImport() bool
+	// IsForget returns true if the resource is removed from the state without being destroyed
+	IsForget() bool
 	GetBefore() map[string]interface{}
 	GetAfter() map[string]interface{}
 	GetBeforeChangedOnly() map[string]interface{}
@@ -21,28 +34,44 @@ type ResourcePlan interface {
 	GetComputed() map[string]interface{}
 	GetName() string
 	GetType() string
+	GetIndex() interface{}
+	GetModuleAddress() string
 	GetAddress() string
 }
 
-func NewResourcePlans(path string, isJSON bool) ([]ResourcePlan, error) {
-	var result []ResourcePlan
+// Plan is a terraform plan, with the planned changes separate from the changes made outside of Terraform
+type Plan struct {
+	ResourcePlans []ResourcePlan
+
+	// Drift is the changes made outside of Terraform, which are only supported in JSON plans
+	Drift []ResourcePlan
+}
+
+func NewPlan(path string, isJSON bool) (Plan, error) {
 	var data io.Reader
 	var err error
 
 	if path != "" {
 		data, err = os.Open(path)
 		if err != nil {
-			return result, err
+			return Plan{}, err
 		}
 	} else {
 		data = os.Stdin
 	}
 
 	if isJSON {
-		return NewResourcePlansFromJSON(data)
+		return NewPlanFromJSON(data)
 	}
 
-	return NewResourcePlansFromPlanOutput(data)
+	rc, err := NewResourcePlansFromPlanOutput(data)
+	return Plan{ResourcePlans: rc}, err
+}
+
+// NewResourcePlans returns the planned changes, without the changes made outside of Terraform
+func NewResourcePlans(path string, isJSON bool) ([]ResourcePlan, error) {
+	p, err := NewPlan(path, isJSON)
+	return p.ResourcePlans, err
 }
 
 func NewResourcePlansFromPlanOutput(in io.Reader) ([]ResourcePlan, error) {
@@ -60,8 +89,8 @@ func NewResourcePlansFromPlanOutput(in io.Reader) ([]ResourcePlan, error) {
 	return result, nil
 }
 
-func NewResourcePlansFromJSON(in io.Reader) ([]ResourcePlan, error) {
-	var result []ResourcePlan
+func NewPlanFromJSON(in io.Reader) (Plan, error) {
+	var result Plan
 
 	parsed := &tfjson.Plan{}
 	data, err := ioutil.ReadAll(in)
@@ -75,8 +104,17 @@ func NewResourcePlansFromJSON(in io.Reader) ([]ResourcePlan, error) {
 	}
 
 	for _, rc := range parsed.ResourceChanges {
-		result = append(result, NewJSONPlanChange(rc))
+		result.ResourcePlans = append(result.ResourcePlans, NewJSONPlanChange(rc))
+	}
+	for _, rc := range parsed.ResourceDrift {
+		result.Drift = append(result.Drift, NewJSONDriftChange(rc))
 	}
 
 	return result, nil
 }
+
+// NewResourcePlansFromJSON returns the planned changes, without the changes made outside of Terraform
+func NewResourcePlansFromJSON(in io.Reader) ([]ResourcePlan, error) {
+	p, err := NewPlanFromJSON(in)
+	return p.ResourcePlans, err
+}
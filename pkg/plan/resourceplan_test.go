@@ -0,0 +1,244 @@
+package plan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type actions struct {
+	Create  bool
+	Delete  bool
+	Update  bool
+	Replace bool
+	Read    bool
+}
+
+func planActions(rc []ResourcePlan) map[string]actions {
+	result := make(map[string]actions)
+	for _, r := range rc {
+		result[r.GetAddress()] = actions{
+			Create:  r.IsCreate(),
+			Delete:  r.IsDelete(),
+			Update:  r.IsUpdate(),
+			Replace: r.IsReplace(),
+			Read:    r.IsRead(),
+		}
+	}
+	return result
+}
+
+// TestResourcePlanActions verifies both parsers report the same actions for the same plan
+func TestResourcePlanActions(t *testing.T) {
+	planOutput := `
+Terraform will perform the following actions:
+
+  # aws_instance.web will be updated in-place
+  ~ resource "aws_instance" "web" {
+      ~ instance_type = "t3.small" -> "t3.large"
+    }
+
+  # aws_db_instance.main must be replaced
+-/+ resource "aws_db_instance" "main" {
+      ~ engine = "mysql" -> "postgres" # forces replacement
+    }
+
+  # aws_s3_bucket.logs will be created
+  + resource "aws_s3_bucket" "logs" {
+      + bucket = "logs"
+    }
+
+  # aws_s3_bucket.old will be destroyed
+  - resource "aws_s3_bucket" "old" {
+      - bucket = "old" -> null
+    }
+
+  # data.aws_ami.ubuntu will be read during apply
+  # (config refers to values not yet known)
+ <= data "aws_ami" "ubuntu" {
+      + id     = (known after apply)
+      + owners = [
+          + "099720109477",
+        ]
+    }
+
+Plan: 2 to add, 1 to change, 2 to destroy.
+`
+	planJSON := `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {"address": "aws_instance.web", "type": "aws_instance", "name": "web", "change": {"actions": ["update"]}},
+    {"address": "aws_db_instance.main", "type": "aws_db_instance", "name": "main", "change": {"actions": ["delete", "create"]}},
+    {"address": "aws_s3_bucket.logs", "type": "aws_s3_bucket", "name": "logs", "change": {"actions": ["create"]}},
+    {"address": "aws_s3_bucket.old", "type": "aws_s3_bucket", "name": "old", "change": {"actions": ["delete"]}},
+    {"address": "data.aws_ami.ubuntu", "mode": "data", "type": "aws_ami", "name": "ubuntu", "change": {"actions": ["read"]}}
+  ]
+}`
+	expected := map[string]actions{
+		"aws_instance.web":     {Update: true},
+		"aws_db_instance.main": {Replace: true},
+		"aws_s3_bucket.logs":   {Create: true},
+		"aws_s3_bucket.old":    {Delete: true},
+		"data.aws_ami.ubuntu":  {Read: true},
+	}
+
+	fromOutput, err := NewResourcePlansFromPlanOutput(strings.NewReader(planOutput))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing plan output: %v", err)
+	}
+	if diff := cmp.Diff(planActions(fromOutput), expected); diff != "" {
+		t.Errorf("plan output (-got, +expected)\n%s", diff)
+	}
+
+	fromJSON, err := NewResourcePlansFromJSON(strings.NewReader(planJSON))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing JSON plan: %v", err)
+	}
+	if diff := cmp.Diff(planActions(fromJSON), expected); diff != "" {
+		t.Errorf("JSON plan (-got, +expected)\n%s", diff)
+	}
+}
+
+func TestGetReplacePaths(t *testing.T) {
+	planOutput := `
+Terraform will perform the following actions:
+
+  # aws_instance.web must be replaced
+-/+ resource "aws_instance" "web" {
+      ~ ami           = "ami-1" -> "ami-2" # forces replacement
+      ~ instance_type = "t3.small" -> "t3.large"
+      ~ root_block_device {
+          ~ volume_type = "gp2" -> "gp3" # forces replacement
+        }
+    }
+
+Plan: 1 to add, 0 to change, 1 to destroy.
+`
+	planJSON := `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {
+      "address": "aws_instance.web",
+      "type": "aws_instance",
+      "name": "web",
+      "change": {
+        "actions": ["delete", "create"],
+        "replace_paths": [["ami"], ["root_block_device", 0, "volume_type"]]
+      }
+    }
+  ]
+}`
+
+	fromOutput, err := NewResourcePlansFromPlanOutput(strings.NewReader(planOutput))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing plan output: %v", err)
+	}
+	if diff := cmp.Diff(fromOutput[0].GetReplacePaths(), []string{"ami", "root_block_device.volume_type"}); diff != "" {
+		t.Errorf("plan output (-got, +expected)\n%s", diff)
+	}
+
+	fromJSON, err := NewResourcePlansFromJSON(strings.NewReader(planJSON))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing JSON plan: %v", err)
+	}
+	if diff := cmp.Diff(fromJSON[0].GetReplacePaths(), []string{"ami", "root_block_device[0].volume_type"}); diff != "" {
+		t.Errorf("JSON plan (-got, +expected)\n%s", diff)
+	}
+}
+
+func TestResourcePlanOperations(t *testing.T) {
+	planJSON := `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {"address": "module.network.aws_subnet.private", "previous_address": "module.vpc.aws_subnet.private", "type": "aws_subnet", "name": "private", "change": {"actions": ["no-op"]}},
+    {"address": "aws_s3_bucket.logs", "type": "aws_s3_bucket", "name": "logs", "change": {"actions": ["no-op"], "importing": {"id": "logs"}}},
+    {"address": "aws_db_instance.main", "type": "aws_db_instance", "name": "main", "change": {"actions": ["forget"]}}
+  ]
+}`
+	type operations struct {
+		PreviousAddress string
+		Import          bool
+		Forget          bool
+	}
+	expected := map[string]operations{
+		"module.network.aws_subnet.private": {PreviousAddress: "module.vpc.aws_subnet.private"},
+		"aws_s3_bucket.logs":                {Import: true},
+		"aws_db_instance.main":              {Forget: true},
+	}
+
+	rc, err := NewResourcePlansFromJSON(strings.NewReader(planJSON))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing JSON plan: %v", err)
+	}
+	got := make(map[string]operations)
+	for _, r := range rc {
+		got[r.GetAddress()] = operations{
+			PreviousAddress: r.GetPreviousAddress(),
+			Import:          r.IsImport(),
+			Forget:          r.IsForget(),
+		}
+	}
+	if diff := cmp.Diff(got, expected); diff != "" {
+		t.Errorf("(-got, +expected)\n%s", diff)
+	}
+}
+
+func TestResourcePlanDrift(t *testing.T) {
+	planJSON := `{
+  "format_version": "1.2",
+  "resource_drift": [
+    {
+      "address": "aws_autoscaling_group.web",
+      "type": "aws_autoscaling_group",
+      "name": "web",
+      "change": {
+        "actions": ["update"],
+        "before": {"desired_capacity": 2, "health_check_type": "ELB", "max_size": 10, "tags": {"CostCenter": "42", "Owner": "platform", "Team": "web"}},
+        "after": {"desired_capacity": 5, "max_size": 10, "tags": {"Owner": "someone", "Team": "web"}}
+      }
+    }
+  ],
+  "resource_changes": [
+    {"address": "aws_instance.web", "type": "aws_instance", "name": "web", "change": {"actions": ["update"]}}
+  ]
+}`
+
+	p, err := NewPlanFromJSON(strings.NewReader(planJSON))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing JSON plan: %v", err)
+	}
+
+	if len(p.ResourcePlans) != 1 || p.ResourcePlans[0].GetAddress() != "aws_instance.web" {
+		t.Errorf("Expected only aws_instance.web to be a planned change, got %v", p.ResourcePlans)
+	}
+	if len(p.Drift) != 1 || p.Drift[0].GetAddress() != "aws_autoscaling_group.web" {
+		t.Fatalf("Expected only aws_autoscaling_group.web to be drifted, got %v", p.Drift)
+	}
+
+	rc, err := NewResourcePlansFromJSON(strings.NewReader(planJSON))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing JSON plan: %v", err)
+	}
+	if len(rc) != 1 || rc[0].GetAddress() != "aws_instance.web" {
+		t.Errorf("Expected resource plans without drift, got %v", rc)
+	}
+
+	// Attributes removed outside of Terraform are nil after the change
+	expectedAfter := map[string]interface{}{
+		"desired_capacity":  float64(5),
+		"health_check_type": nil,
+		"tags":              map[string]interface{}{"CostCenter": nil, "Owner": "someone"},
+	}
+	if diff := cmp.Diff(p.Drift[0].GetAfterChangedOnly(), expectedAfter); diff != "" {
+		t.Errorf("after (-got, +expected)\n%s", diff)
+	}
+	expectedBefore := map[string]interface{}{
+		"desired_capacity":  float64(2),
+		"health_check_type": "ELB",
+		"tags":              map[string]interface{}{"CostCenter": "42", "Owner": "platform"},
+	}
+	if diff := cmp.Diff(p.Drift[0].GetBeforeChangedOnly(), expectedBefore); diff != "" {
+		t.Errorf("before (-got, +expected)\n%s", diff)
+	}
+}
@@ -4,8 +4,14 @@ import (
 	"github.com/hashicorp/terraform-json"
 )
 
+// actionForget is the action of resources removed from the state with a removed block
+const actionForget tfjson.Action = "forget"
+
 type jsonPlanChange struct {
 	ResourceChange *tfjson.ResourceChange
+
+	// Drift is true for changes made outside of Terraform
+	Drift bool
 }
 
 func NewJSONPlanChange(json *tfjson.ResourceChange) *jsonPlanChange {
@@ -14,6 +20,14 @@ func NewJSONPlanChange(json *tfjson.ResourceChange) *jsonPlanChange {
 	}
 }
 
+// NewJSONDriftChange returns a change made outside of Terraform, from the resource drift of the plan
+func NewJSONDriftChange(json *tfjson.ResourceChange) *jsonPlanChange {
+	return &jsonPlanChange{
+		ResourceChange: json,
+		Drift:          true,
+	}
+}
+
 func (j *jsonPlanChange) IsCreate() bool {
 	return j.ResourceChange.Change.Actions.Create()
 }
@@ -26,10 +40,50 @@ func (j *jsonPlanChange) IsNoOp() bool {
 	return j.ResourceChange.Change.Actions.NoOp()
 }
 
+func (j *jsonPlanChange) IsRead() bool {
+	return j.ResourceChange.Change.Actions.Read()
+}
+
 func (j *jsonPlanChange) IsUpdate() bool {
 	return j.ResourceChange.Change.Actions.Update()
 }
 
+// IsReplace returns true if the resource is destroyed and recreated, in either order
+func (j *jsonPlanChange) IsReplace() bool {
+	return j.ResourceChange.Change.Actions.Replace()
+}
+
+// GetReplacePaths formats the replace paths as dotted paths, for example ebs_block_device[0].volume_size
+func (j *jsonPlanChange) GetReplacePaths() []string {
+	var result []string
+	for _, p := range j.ResourceChange.Change.ReplacePaths {
+		steps, ok := p.([]interface{})
+		if !ok {
+			continue
+		}
+		result = append(result, formatPath(steps))
+	}
+	return result
+}
+
+func (j *jsonPlanChange) GetPreviousAddress() string {
+	return j.ResourceChange.PreviousAddress
+}
+
+func (j *jsonPlanChange) IsImport() bool {
+	return j.ResourceChange.Change.Importing != nil
+}
+
+// IsForget returns true if the actions include forget, which terraform-json does not have a constant for
+func (j *jsonPlanChange) IsForget() bool {
+	for _, action := range j.ResourceChange.Change.Actions {
+		if action == actionForget {
+			return true
+		}
+	}
+	return false
+}
+
 func (j *jsonPlanChange) GetBefore() map[string]interface{} {
 	if j.ResourceChange.Change.Before != nil {
 		return j.ResourceChange.Change.Before.(map[string]interface{})
@@ -44,11 +98,21 @@ func (j *jsonPlanChange) GetAfter() map[string]interface{} {
 	return map[string]interface{}{}
 }
 
+// GetBeforeChangedOnly returns the values before the change
+// For changes made outside of Terraform, only the values that changed are returned
 func (j *jsonPlanChange) GetBeforeChangedOnly() map[string]interface{} {
+	if j.Drift {
+		return changedValues(j.GetAfter(), j.GetBefore())
+	}
 	return j.GetBefore()
 }
 
+// GetAfterChangedOnly returns the values after the change
+// For changes made outside of Terraform, only the values that changed are returned
 func (j *jsonPlanChange) GetAfterChangedOnly() map[string]interface{} {
+	if j.Drift {
+		return changedValues(j.GetBefore(), j.GetAfter())
+	}
 	return j.GetAfter()
 }
 
@@ -67,6 +131,19 @@ func (j *jsonPlanChange) GetType() string {
 	return j.ResourceChange.Type
 }
 
+// GetIndex returns the index as an int for "count" resources and a string for "for_each" resources
+func (j *jsonPlanChange) GetIndex() interface{} {
+	// JSON numbers are decoded as float64
+	if i, ok := j.ResourceChange.Index.(float64); ok {
+		return int(i)
+	}
+	return j.ResourceChange.Index
+}
+
+func (j *jsonPlanChange) GetModuleAddress() string {
+	return j.ResourceChange.ModuleAddress
+}
+
 func (j *jsonPlanChange) GetAddress() string {
 	return j.ResourceChange.Address
 }
@@ -26,8 +26,43 @@ func (t *tfPlanChange) IsNoOp() bool {
 	return t.ResourceChange.UpdateType == tfplanparse.NoOpResource
 }
 
+func (t *tfPlanChange) IsRead() bool {
+	return t.ResourceChange.UpdateType == tfplanparse.ReadResource
+}
+
+// IsUpdate returns true if the resource is updated in place
+// Replaced resources are not updates, to match terraform-json
 func (t *tfPlanChange) IsUpdate() bool {
-	return t.ResourceChange.UpdateType == tfplanparse.UpdateInPlaceResource || t.ResourceChange.UpdateType == tfplanparse.ForceReplaceResource
+	return t.ResourceChange.UpdateType == tfplanparse.UpdateInPlaceResource
+}
+
+func (t *tfPlanChange) IsReplace() bool {
+	return t.ResourceChange.UpdateType == tfplanparse.ForceReplaceResource
+}
+
+// GetReplacePaths returns the paths of the attributes marked with "# forces replacement"
+// Lists are reported by their own path, as the plan output does not include element indexes
+func (t *tfPlanChange) GetReplacePaths() []string {
+	var result []string
+	for _, a := range t.ResourceChange.AttributeChanges {
+		result = append(result, replacePaths("", a)...)
+	}
+	return result
+}
+
+// GetPreviousAddress returns an empty string, as moved resources are only supported in JSON plans
+func (t *tfPlanChange) GetPreviousAddress() string {
+	return ""
+}
+
+// IsImport returns false, as imports are only supported in JSON plans
+func (t *tfPlanChange) IsImport() bool {
+	return false
+}
+
+// IsForget returns false, as forgotten resources are only supported in JSON plans
+func (t *tfPlanChange) IsForget() bool {
+	return false
 }
 
 func (t *tfPlanChange) GetBefore() map[string]interface{} {
@@ -58,6 +93,16 @@ func (t *tfPlanChange) GetType() string {
 	return t.ResourceChange.Type
 }
 
+func (t *tfPlanChange) GetIndex() interface{} {
+	return t.ResourceChange.Index
+}
+
+// GetModuleAddress parses the module address from the full address, as
+// tfplanparse only records the first module of nested modules
+func (t *tfPlanChange) GetModuleAddress() string {
+	return moduleAddressFromAddress(t.ResourceChange.Address)
+}
+
 func (t *tfPlanChange) GetAddress() string {
 	return t.ResourceChange.Address
 }
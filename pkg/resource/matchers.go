@@ -0,0 +1,834 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/netip"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/drlau/akashi/pkg/ruleset"
+	"github.com/hashicorp/go-version"
+)
+
+// matcherResult is the result of checking a value against a single matcher
+// Expected describes the matcher, and is shown in the diff if the matcher failed
+type matcherResult struct {
+	Expected string
+	OK       bool
+}
+
+func newMatcherResult(expected string, ok bool) matcherResult {
+	return matcherResult{
+		Expected: expected,
+		OK:       ok,
+	}
+}
+
+// hasMatchers returns true if the EnforceChange sets any matcher other than value and matchAny
+func hasMatchers(e ruleset.EnforceChange) bool {
+	return e.Exists != nil || e.Null != nil || e.Unordered ||
+		e.ContainsAll != nil || e.ContainsAny != nil || e.SubsetOf != nil ||
+		e.MatchRegex != "" || e.NotMatchRegex != "" ||
+		e.HasPrefix != "" || e.HasSuffix != "" || e.Contains != "" || e.EqualFold != "" ||
+		e.WithinCIDR != nil || e.NotOverlapCIDR != nil ||
+		e.VersionConstraint != "" ||
+		e.JSON != nil ||
+		e.Min != nil || e.Max != nil || e.GreaterThan != nil || e.LessThan != nil ||
+		e.Not != nil || e.AllOf != nil || e.AnyOf != nil || e.OneOf != nil ||
+		hasElementMatchers(e)
+}
+
+// hasValueMatchers returns true if the EnforceChange sets any matcher on the value itself, rather than on its nested attributes
+func hasValueMatchers(e ruleset.EnforceChange) bool {
+	return e.Value != nil || e.MatchAny != nil || hasMatchers(e) || hasAttributeMatchers(e) || hasRelationalMatchers(e)
+}
+
+// hasElementMatchers returns true if the EnforceChange sets any matcher that applies to the elements of a list
+func hasElementMatchers(e ruleset.EnforceChange) bool {
+	return e.Each != nil || e.Any != nil || e.None != nil
+}
+
+// hasAttributeMatchers returns true if the EnforceChange sets any matcher that compares the value to another attribute
+func hasAttributeMatchers(e ruleset.EnforceChange) bool {
+	return e.EqualsAttr != "" || e.NotEqualsAttr != "" || e.MinAttr != "" || e.MaxAttr != "" || e.GreaterThanAttr != "" || e.LessThanAttr != ""
+}
+
+// withoutAttributeMatchers returns a copy of the EnforceChange without the attribute matchers
+func withoutAttributeMatchers(e ruleset.EnforceChange) ruleset.EnforceChange {
+	e.EqualsAttr, e.NotEqualsAttr, e.MinAttr, e.MaxAttr, e.GreaterThanAttr, e.LessThanAttr = "", "", "", "", "", ""
+	return e
+}
+
+// hasRelationalMatchers returns true if the EnforceChange sets any matcher that compares the value to its value before the change
+func hasRelationalMatchers(e ruleset.EnforceChange) bool {
+	return e.IncreaseOnly || e.DecreaseOnly || e.Unchanged || e.MaxDelta != nil || e.MaxPercentChange != nil
+}
+
+// withoutRelationalMatchers returns a copy of the EnforceChange without the relational matchers
+func withoutRelationalMatchers(e ruleset.EnforceChange) ruleset.EnforceChange {
+	e.IncreaseOnly, e.DecreaseOnly, e.Unchanged, e.MaxDelta, e.MaxPercentChange = false, false, false, nil, nil
+	return e
+}
+
+// withoutElementMatchers returns a copy of the EnforceChange without the each, any and none matchers
+func withoutElementMatchers(e ruleset.EnforceChange) ruleset.EnforceChange {
+	e.Each, e.Any, e.None = nil, nil, nil
+	return e
+}
+
+// matchValue checks the value of the key against every matcher set on the EnforceChange
+// If the value does not match, a description of the first failing matcher is returned
+func matchValue(e ruleset.EnforceChange, key string, opts *checkOptions, v interface{}) (string, bool) {
+	return firstFailure(evaluateMatchers(e, key, opts, v))
+}
+
+// firstFailure returns the description of the first failing matcher
+func firstFailure(results []matcherResult) (string, bool) {
+	for _, result := range results {
+		if !result.OK {
+			return result.Expected, false
+		}
+	}
+
+	return "", true
+}
+
+// evaluateMatchers checks the value of the key against every matcher set on the EnforceChange
+// The key and options are used by matchers that compare against other values of the resource, and by nested rules
+func evaluateMatchers(e ruleset.EnforceChange, key string, opts *checkOptions, v interface{}) []matcherResult {
+	var results []matcherResult
+	if e.Exists != nil {
+		results = append(results, newMatcherResult(describeExists(*e.Exists), *e.Exists))
+	}
+	if e.Null != nil {
+		results = append(results, newMatcherResult(describeNull(*e.Null), (v == nil) == *e.Null))
+	}
+	if e.Value != nil {
+		results = append(results, newMatcherResult(describeOrder(fmt.Sprintf("%v", e.Value), e.Unordered), equalOrder(e.Value, v, e.Unordered)))
+	}
+	if e.MatchAny != nil {
+		results = append(results, newMatcherResult(describeOrder(fmt.Sprintf("one of: %v", e.MatchAny), e.Unordered), matchAny(e.MatchAny, v, e.Unordered)))
+	}
+	if e.ContainsAll != nil {
+		results = append(results, newMatcherResult(matchContainsAll(e.ContainsAll, v)))
+	}
+	if e.ContainsAny != nil {
+		results = append(results, newMatcherResult(matchContainsAny(e.ContainsAny, v)))
+	}
+	if e.SubsetOf != nil {
+		results = append(results, newMatcherResult(matchSubsetOf(e.SubsetOf, v)))
+	}
+	if e.MatchRegex != "" {
+		results = append(results, newMatcherResult(matchRegex(e.MatchRegex, v, true)))
+	}
+	if e.NotMatchRegex != "" {
+		results = append(results, newMatcherResult(matchRegex(e.NotMatchRegex, v, false)))
+	}
+	if e.HasPrefix != "" {
+		results = append(results, newMatcherResult(matchString(fmt.Sprintf("has prefix %q", e.HasPrefix), v, func(s string) bool {
+			return strings.HasPrefix(s, e.HasPrefix)
+		})))
+	}
+	if e.HasSuffix != "" {
+		results = append(results, newMatcherResult(matchString(fmt.Sprintf("has suffix %q", e.HasSuffix), v, func(s string) bool {
+			return strings.HasSuffix(s, e.HasSuffix)
+		})))
+	}
+	if e.Contains != "" {
+		results = append(results, newMatcherResult(matchString(fmt.Sprintf("contains %q", e.Contains), v, func(s string) bool {
+			return strings.Contains(s, e.Contains)
+		})))
+	}
+	if e.EqualFold != "" {
+		results = append(results, newMatcherResult(matchString(fmt.Sprintf("equals %q ignoring case", e.EqualFold), v, func(s string) bool {
+			return strings.EqualFold(s, e.EqualFold)
+		})))
+	}
+	if e.WithinCIDR != nil {
+		results = append(results, newMatcherResult(matchCIDR(fmt.Sprintf("within CIDR %v", e.WithinCIDR), e.WithinCIDR, v, func(prefix netip.Prefix, ranges []netip.Prefix) bool {
+			for _, r := range ranges {
+				if r.Bits() <= prefix.Bits() && r.Contains(prefix.Addr()) {
+					return true
+				}
+			}
+			return false
+		})))
+	}
+	if e.NotOverlapCIDR != nil {
+		results = append(results, newMatcherResult(matchCIDR(fmt.Sprintf("not overlapping CIDR %v", e.NotOverlapCIDR), e.NotOverlapCIDR, v, func(prefix netip.Prefix, ranges []netip.Prefix) bool {
+			for _, r := range ranges {
+				if r.Overlaps(prefix) {
+					return false
+				}
+			}
+			return true
+		})))
+	}
+	if e.VersionConstraint != "" {
+		results = append(results, newMatcherResult(matchVersionConstraint(e.VersionConstraint, v)))
+	}
+	if e.Min != nil || e.Max != nil || e.GreaterThan != nil || e.LessThan != nil {
+		results = append(results, newMatcherResult(matchNumber(e, v)))
+	}
+	if hasAttributeMatchers(e) {
+		results = append(results, newMatcherResult(matchAttributes(e, opts, key, v)))
+	}
+	if hasRelationalMatchers(e) {
+		results = append(results, newMatcherResult(matchPrevious(e, key, opts, v)))
+	}
+	if e.Not != nil {
+		results = append(results, newMatcherResult(matchNot(*e.Not, key, opts, v)))
+	}
+	if e.AllOf != nil {
+		results = append(results, newMatcherResult(matchAllOf(e.AllOf, key, opts, v)))
+	}
+	if e.AnyOf != nil {
+		results = append(results, newMatcherResult(matchAnyOf(e.AnyOf, key, opts, v)))
+	}
+	if e.OneOf != nil {
+		results = append(results, newMatcherResult(matchOneOf(e.OneOf, key, opts, v)))
+	}
+	if hasElementMatchers(e) {
+		results = append(results, newMatcherResult(matchElements(e, key, opts, v)))
+	}
+	if e.JSON != nil {
+		results = append(results, newMatcherResult(matchJSON(*e.JSON, key, opts, v)))
+	}
+	if len(e.EnforceChange) > 0 {
+		results = append(results, newMatcherResult(matchNested(e.EnforceChange, key, opts, v)))
+	}
+
+	return results
+}
+
+// describeMatchers joins the descriptions of every matcher
+func describeMatchers(results []matcherResult) string {
+	var descriptions []string
+	for _, result := range results {
+		descriptions = append(descriptions, result.Expected)
+	}
+	return strings.Join(descriptions, " and ")
+}
+
+// matchAbsent checks the exists and null matchers for an attribute that is not in the plan
+// Absent attributes are treated as null, as plans parsed from text omit null attributes
+func matchAbsent(e ruleset.EnforceChange) (string, bool) {
+	if e.Exists != nil && *e.Exists {
+		return describeExists(true), false
+	}
+	if e.Null != nil && !*e.Null {
+		return describeNull(false), false
+	}
+
+	return "", true
+}
+
+func describeExists(exists bool) string {
+	if exists {
+		return "present"
+	}
+	return "absent"
+}
+
+func describeNull(null bool) string {
+	if null {
+		return "null"
+	}
+	return "not null"
+}
+
+func matchAny(values []interface{}, v interface{}, unordered bool) bool {
+	for _, val := range values {
+		if equalOrder(val, v, unordered) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalOrder checks if the values are equal
+// If unordered is set, lists are equal if they have the same elements in any order
+func equalOrder(expected, v interface{}, unordered bool) bool {
+	if !unordered {
+		return equal(expected, v)
+	}
+
+	expectedList, ok := expected.([]interface{})
+	if !ok {
+		return equal(expected, v)
+	}
+	list, ok := v.([]interface{})
+	if !ok || len(list) != len(expectedList) {
+		return false
+	}
+
+	// Each element can only be matched once, so duplicates must appear the same number of times
+	matched := make([]bool, len(list))
+	for _, e := range expectedList {
+		found := false
+		for i, val := range list {
+			if !matched[i] && equalElement(e, val) {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func describeOrder(expected string, unordered bool) string {
+	if unordered {
+		return fmt.Sprintf("%s (in any order)", expected)
+	}
+	return expected
+}
+
+// contains returns true if any element of the list is equal to the value
+func contains(list []interface{}, v interface{}) bool {
+	for _, val := range list {
+		if equalElement(val, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalElement checks if the list elements are equal
+// Numbers are compared by value, as the ruleset has ints, JSON plans have float64 and text plans have strings
+func equalElement(expected, v interface{}) bool {
+	if equal(expected, v) {
+		return true
+	}
+
+	_, expectedString := expected.(string)
+	_, valueString := v.(string)
+	if expectedString && valueString {
+		return false
+	}
+	expectedNumber, ok := toNumber(expected)
+	if !ok {
+		return false
+	}
+	number, ok := toNumber(v)
+	return ok && number == expectedNumber
+}
+
+// matchContainsAll checks that the value is a list containing every expected element
+func matchContainsAll(expected []interface{}, v interface{}) (string, bool) {
+	description := fmt.Sprintf("contains all of: %v", expected)
+	list, ok := v.([]interface{})
+	if !ok {
+		return fmt.Sprintf("%s (not a list)", description), false
+	}
+
+	for _, e := range expected {
+		if !contains(list, e) {
+			return description, false
+		}
+	}
+	return description, true
+}
+
+// matchContainsAny checks that the value is a list containing at least one expected element
+func matchContainsAny(expected []interface{}, v interface{}) (string, bool) {
+	description := fmt.Sprintf("contains any of: %v", expected)
+	list, ok := v.([]interface{})
+	if !ok {
+		return fmt.Sprintf("%s (not a list)", description), false
+	}
+
+	for _, e := range expected {
+		if contains(list, e) {
+			return description, true
+		}
+	}
+	return description, false
+}
+
+// matchSubsetOf checks that the value is a list with every element in the allowed elements
+func matchSubsetOf(allowed []interface{}, v interface{}) (string, bool) {
+	description := fmt.Sprintf("subset of: %v", allowed)
+	list, ok := v.([]interface{})
+	if !ok {
+		return fmt.Sprintf("%s (not a list)", description), false
+	}
+
+	for _, val := range list {
+		if !contains(allowed, val) {
+			return description, false
+		}
+	}
+	return description, true
+}
+
+// matchRegex checks if the value matches the pattern, or does not match if match is false
+// Non-string values are formatted before matching
+func matchRegex(pattern string, v interface{}, match bool) (string, bool) {
+	expected := fmt.Sprintf("matches regex %s", pattern)
+	if !match {
+		expected = fmt.Sprintf("does not match regex %s", pattern)
+	}
+
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return fmt.Sprintf("%s (invalid regex: %v)", expected, err), false
+	}
+
+	return expected, re.MatchString(fmt.Sprintf("%v", v)) == match
+}
+
+// regexes caches the compiled patterns of matchRegex and notMatchRegex by pattern
+var regexes sync.Map
+
+// compileRegex compiles the pattern, or returns the pattern compiled by an earlier call
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexes.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexes.Store(pattern, re)
+	return re, nil
+}
+
+// matchString checks that the value is a string matching the condition
+func matchString(description string, v interface{}, match func(string) bool) (string, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Sprintf("%s (not a string)", description), false
+	}
+
+	return description, match(s)
+}
+
+// matchCIDR checks that the value is a CIDR, or a list of CIDRs, matching the condition for the ranges
+// IPv4 and IPv6 CIDRs are supported, and values that are not valid CIDRs fail
+func matchCIDR(description string, cidrs []string, v interface{}, match func(netip.Prefix, []netip.Prefix) bool) (string, bool) {
+	ranges := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		r, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return fmt.Sprintf("%s (invalid CIDR in rule: %v)", description, err), false
+		}
+		ranges = append(ranges, r.Masked())
+	}
+
+	values, ok := v.([]interface{})
+	if !ok {
+		values = []interface{}{v}
+	}
+	for _, val := range values {
+		s, ok := val.(string)
+		if !ok {
+			return fmt.Sprintf("%s (%v is not a CIDR)", description, val), false
+		}
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return fmt.Sprintf("%s (%s is not a CIDR)", description, s), false
+		}
+		if !match(prefix.Masked(), ranges) {
+			return fmt.Sprintf("%s (%s does not match)", description, s), false
+		}
+	}
+
+	return description, true
+}
+
+// matchVersionConstraint checks that the value is a version satisfying the constraint
+// Versions with a prerelease suffix, such as 1.27.3-gke.100, also satisfy the constraint if their core version does
+func matchVersionConstraint(constraint string, v interface{}) (string, bool) {
+	description := fmt.Sprintf("version %s", constraint)
+	constraints, err := version.NewConstraint(constraint)
+	if err != nil {
+		return fmt.Sprintf("%s (invalid version constraint: %v)", description, err), false
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Sprintf("%s (not a version)", description), false
+	}
+	ver, err := version.NewVersion(s)
+	if err != nil {
+		return fmt.Sprintf("%s (not a version)", description), false
+	}
+
+	if constraints.Check(ver) {
+		return description, true
+	}
+	return description, ver.Prerelease() != "" && constraints.Check(ver.Core())
+}
+
+// matchNumber checks the value against the min, max, greaterThan and lessThan matchers
+func matchNumber(e ruleset.EnforceChange, v interface{}) (string, bool) {
+	var expected []string
+	if e.Min != nil {
+		expected = append(expected, fmt.Sprintf(">= %s", formatNumber(*e.Min)))
+	}
+	if e.Max != nil {
+		expected = append(expected, fmt.Sprintf("<= %s", formatNumber(*e.Max)))
+	}
+	if e.GreaterThan != nil {
+		expected = append(expected, fmt.Sprintf("> %s", formatNumber(*e.GreaterThan)))
+	}
+	if e.LessThan != nil {
+		expected = append(expected, fmt.Sprintf("< %s", formatNumber(*e.LessThan)))
+	}
+	description := fmt.Sprintf("number %s", strings.Join(expected, " and "))
+
+	n, ok := toNumber(v)
+	if !ok {
+		return fmt.Sprintf("%s (not a number)", description), false
+	}
+
+	switch {
+	case e.Min != nil && n < *e.Min,
+		e.Max != nil && n > *e.Max,
+		e.GreaterThan != nil && n <= *e.GreaterThan,
+		e.LessThan != nil && n >= *e.LessThan:
+		return description, false
+	}
+
+	return description, true
+}
+
+// toNumber converts the value to a float64
+// Plans parsed from text have numbers as strings, while JSON plans have float64
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// matchNot checks that the value does not match the nested matchers
+func matchNot(e ruleset.EnforceChange, key string, opts *checkOptions, v interface{}) (string, bool) {
+	results := evaluateMatchers(e, key, opts, v)
+	_, ok := firstFailure(results)
+
+	return fmt.Sprintf("not (%s)", describeMatchers(results)), !ok
+}
+
+// matchAllOf checks that the value matches every nested matcher
+// If a matcher fails, only the failing matcher is described
+func matchAllOf(es []ruleset.EnforceChange, key string, opts *checkOptions, v interface{}) (string, bool) {
+	var descriptions []string
+	for i, e := range es {
+		results := evaluateMatchers(e, key, opts, v)
+		if expected, ok := firstFailure(results); !ok {
+			return fmt.Sprintf("all of: [%d] %s", i, expected), false
+		}
+		descriptions = append(descriptions, fmt.Sprintf("[%d] %s", i, describeMatchers(results)))
+	}
+
+	return fmt.Sprintf("all of: %s", strings.Join(descriptions, "; ")), true
+}
+
+// matchAnyOf checks that the value matches at least one nested matcher
+// If every matcher fails, the failure of each matcher is described
+func matchAnyOf(es []ruleset.EnforceChange, key string, opts *checkOptions, v interface{}) (string, bool) {
+	var failures []string
+	for i, e := range es {
+		results := evaluateMatchers(e, key, opts, v)
+		expected, ok := firstFailure(results)
+		if ok {
+			return fmt.Sprintf("any of: [%d] %s", i, describeMatchers(results)), true
+		}
+		failures = append(failures, fmt.Sprintf("[%d] %s", i, expected))
+	}
+
+	return fmt.Sprintf("any of: %s", strings.Join(failures, "; ")), false
+}
+
+// matchOneOf checks that the value matches exactly one nested matcher
+// The failure describes every matcher if none matched, or lists the matchers that matched if more than one did
+func matchOneOf(es []ruleset.EnforceChange, key string, opts *checkOptions, v interface{}) (string, bool) {
+	var (
+		matched  []string
+		failures []string
+	)
+	for i, e := range es {
+		if expected, ok := matchValue(e, key, opts, v); ok {
+			matched = append(matched, fmt.Sprintf("[%d]", i))
+		} else {
+			failures = append(failures, fmt.Sprintf("[%d] %s", i, expected))
+		}
+	}
+
+	switch len(matched) {
+	case 0:
+		return fmt.Sprintf("exactly one of: %s", strings.Join(failures, "; ")), false
+	case 1:
+		return fmt.Sprintf("exactly one of: %s matched", matched[0]), true
+	default:
+		return fmt.Sprintf("exactly one of, but %s matched", strings.Join(matched, " and ")), false
+	}
+}
+
+// matchElements checks the each, any and none matchers against the elements of a list
+// It is used when the element matchers are nested in other matchers, so failures can't be recorded by element index
+func matchElements(e ruleset.EnforceChange, key string, opts *checkOptions, v interface{}) (string, bool) {
+	var expected []string
+	if e.Each != nil {
+		expected = append(expected, "each element matches")
+	}
+	if e.Any != nil {
+		expected = append(expected, "any element matches")
+	}
+	if e.None != nil {
+		expected = append(expected, "no element matches")
+	}
+	description := strings.Join(expected, " and ")
+
+	cr := newCompareResult()
+	cr.checkElements(key, ruleset.EnforceChange{Each: e.Each, Any: e.Any, None: e.None}, opts, v)
+	if len(cr.Failed) > 0 {
+		return fmt.Sprintf("%s (%s)", description, formatFailures(cr.Failed)), false
+	}
+
+	return description, true
+}
+
+// describeNone describes the none matcher for an element that matched it
+func describeNone(e ruleset.EnforceChange, key string, opts *checkOptions, v interface{}) string {
+	if description := describeMatchers(evaluateMatchers(e, key, opts, v)); description != "" {
+		return fmt.Sprintf("no element matching %s", description)
+	}
+	return "no element matching the nested rules"
+}
+
+// decodeJSON decodes a JSON string
+// Plans parsed from text already decode jsonencode values, so maps and lists are returned as is
+func decodeJSON(v interface{}) (interface{}, error) {
+	switch casted := v.(type) {
+	case string:
+		var document interface{}
+		if err := json.Unmarshal([]byte(casted), &document); err != nil {
+			return nil, err
+		}
+		return document, nil
+	case map[string]interface{}, []interface{}:
+		return casted, nil
+	default:
+		return nil, fmt.Errorf("%v is not a JSON string", v)
+	}
+}
+
+// matchJSON checks the decoded JSON document against the nested rules
+// It is used when the json matcher is nested in other matchers, so failures are described instead of recorded by path
+func matchJSON(e ruleset.EnforceChange, key string, opts *checkOptions, v interface{}) (string, bool) {
+	document, err := decodeJSON(v)
+	if err != nil {
+		return fmt.Sprintf("JSON document (%v)", err), false
+	}
+
+	cr := newCompareResult()
+	cr.checkValue(key, e, opts, document)
+	if len(cr.Failed) > 0 {
+		return fmt.Sprintf("JSON document matches (%s)", formatFailures(cr.Failed)), false
+	}
+
+	return "JSON document matches", true
+}
+
+// matchNested checks the rules for the nested attributes of a map
+// It is used when nested rules are in other matchers, so failures are described instead of recorded by path
+func matchNested(nested map[string]ruleset.EnforceChange, key string, opts *checkOptions, v interface{}) (string, bool) {
+	keys := make([]string, 0, len(nested))
+	for k := range nested {
+		keys = append(keys, joinPath(key, k))
+	}
+	sort.Strings(keys)
+	description := fmt.Sprintf("rules for %s", strings.Join(keys, ", "))
+
+	cr := newCompareResult()
+	cr.checkValue(key, ruleset.EnforceChange{EnforceChange: nested}, opts, v)
+	if len(cr.Failed) > 0 {
+		return fmt.Sprintf("%s (%s)", description, formatFailures(cr.Failed)), false
+	}
+
+	return description, true
+}
+
+// matchPrevious checks the value of the key against the relational matchers, using its value before the change
+// Computed values are unknown until apply, so the matchers pass if the value is computed, like the attribute matchers
+func matchPrevious(e ruleset.EnforceChange, key string, opts *checkOptions, v interface{}) (string, bool) {
+	if IsComputed(opts.Computed, key) {
+		return fmt.Sprintf("%s (unknown)", describeRelational(e)), true
+	}
+
+	previous, ok := LookupPath(opts.Previous, key)
+	return matchRelational(e, previous, ok, v)
+}
+
+// matchRelational checks the value against the relational matchers, using the value before the change
+// Values without a previous value fail, as there is nothing to compare against
+func matchRelational(e ruleset.EnforceChange, previous interface{}, hasPrevious bool, v interface{}) (string, bool) {
+	description := describeRelational(e)
+	if !hasPrevious {
+		return fmt.Sprintf("%s (no previous value)", description), false
+	}
+	description = fmt.Sprintf("%s from %v", description, previous)
+
+	if e.Unchanged && !equal(previous, v) {
+		return description, false
+	}
+	if !e.IncreaseOnly && !e.DecreaseOnly && e.MaxDelta == nil && e.MaxPercentChange == nil {
+		return description, true
+	}
+
+	before, ok := toNumber(previous)
+	if !ok {
+		return fmt.Sprintf("%s (previous value is not a number)", description), false
+	}
+	after, ok := toNumber(v)
+	if !ok {
+		return fmt.Sprintf("%s (not a number)", description), false
+	}
+
+	delta := math.Abs(after - before)
+	switch {
+	case e.IncreaseOnly && after < before,
+		e.DecreaseOnly && after > before,
+		e.MaxDelta != nil && delta > *e.MaxDelta:
+		return description, false
+	case e.MaxPercentChange != nil:
+		// Any change from zero is an infinite percentage
+		if before == 0 {
+			return description, delta == 0
+		}
+		return description, delta/math.Abs(before)*100 <= *e.MaxPercentChange
+	}
+
+	return description, true
+}
+
+// describeRelational joins the descriptions of the relational matchers
+func describeRelational(e ruleset.EnforceChange) string {
+	var expected []string
+	if e.Unchanged {
+		expected = append(expected, "unchanged")
+	}
+	if e.IncreaseOnly {
+		expected = append(expected, "increase only")
+	}
+	if e.DecreaseOnly {
+		expected = append(expected, "decrease only")
+	}
+	if e.MaxDelta != nil {
+		expected = append(expected, fmt.Sprintf("change of at most %s", formatNumber(*e.MaxDelta)))
+	}
+	if e.MaxPercentChange != nil {
+		expected = append(expected, fmt.Sprintf("change of at most %s%%", formatNumber(*e.MaxPercentChange)))
+	}
+	return strings.Join(expected, " and ")
+}
+
+// attributeMatcher compares the value to the value of the referenced attribute
+type attributeMatcher struct {
+	Attribute   string
+	Description string
+	Numeric     bool
+	Match       func(v, attribute interface{}) bool
+}
+
+func attributeMatchers(e ruleset.EnforceChange) []attributeMatcher {
+	var matchers []attributeMatcher
+	if e.EqualsAttr != "" {
+		matchers = append(matchers, attributeMatcher{Attribute: e.EqualsAttr, Description: "equal to", Match: equal})
+	}
+	if e.NotEqualsAttr != "" {
+		matchers = append(matchers, attributeMatcher{Attribute: e.NotEqualsAttr, Description: "not equal to", Match: func(v, attribute interface{}) bool {
+			return !equal(v, attribute)
+		}})
+	}
+	if e.MinAttr != "" {
+		matchers = append(matchers, attributeMatcher{Attribute: e.MinAttr, Description: ">=", Numeric: true, Match: func(v, attribute interface{}) bool {
+			return v.(float64) >= attribute.(float64)
+		}})
+	}
+	if e.MaxAttr != "" {
+		matchers = append(matchers, attributeMatcher{Attribute: e.MaxAttr, Description: "<=", Numeric: true, Match: func(v, attribute interface{}) bool {
+			return v.(float64) <= attribute.(float64)
+		}})
+	}
+	if e.GreaterThanAttr != "" {
+		matchers = append(matchers, attributeMatcher{Attribute: e.GreaterThanAttr, Description: ">", Numeric: true, Match: func(v, attribute interface{}) bool {
+			return v.(float64) > attribute.(float64)
+		}})
+	}
+	if e.LessThanAttr != "" {
+		matchers = append(matchers, attributeMatcher{Attribute: e.LessThanAttr, Description: "<", Numeric: true, Match: func(v, attribute interface{}) bool {
+			return v.(float64) < attribute.(float64)
+		}})
+	}
+	return matchers
+}
+
+// matchAttributes checks the value against the attribute matchers, using the other values of the resource
+// Computed values are unknown until apply, so a matcher passes if either the value or the attribute is computed
+func matchAttributes(e ruleset.EnforceChange, opts *checkOptions, key string, v interface{}) (string, bool) {
+	var descriptions []string
+	for _, m := range attributeMatchers(e) {
+		description := fmt.Sprintf("%s attribute %s", m.Description, m.Attribute)
+		if IsComputed(opts.Computed, key) || IsComputed(opts.Computed, m.Attribute) {
+			descriptions = append(descriptions, fmt.Sprintf("%s (unknown)", description))
+			continue
+		}
+
+		attribute, ok := LookupPath(opts.Values, m.Attribute)
+		if !ok {
+			return fmt.Sprintf("%s (attribute not present)", description), false
+		}
+		description = fmt.Sprintf("%s (%v)", description, attribute)
+
+		value := v
+		if m.Numeric {
+			n, ok := toNumber(v)
+			if !ok {
+				return fmt.Sprintf("%s (not a number)", description), false
+			}
+			a, ok := toNumber(attribute)
+			if !ok {
+				return fmt.Sprintf("%s (attribute is not a number)", description), false
+			}
+			value, attribute = n, a
+		}
+		if !m.Match(value, attribute) {
+			return description, false
+		}
+		descriptions = append(descriptions, description)
+	}
+
+	return strings.Join(descriptions, " and "), true
+}
@@ -0,0 +1,960 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/drlau/akashi/pkg/ruleset"
+)
+
+func TestMatchValue(t *testing.T) {
+	cases := map[string]struct {
+		enforced        ruleset.EnforceChange
+		value           interface{}
+		expected        bool
+		expectedFailure string
+	}{
+		"no matchers": {
+			enforced: ruleset.EnforceChange{},
+			value:    "value",
+			expected: true,
+		},
+		"exists with present value": {
+			enforced: ruleset.EnforceChange{
+				Exists: boolPointer(true),
+			},
+			value:    "",
+			expected: true,
+		},
+		"exists false with present value": {
+			enforced: ruleset.EnforceChange{
+				Exists: boolPointer(false),
+			},
+			value:           "value",
+			expected:        false,
+			expectedFailure: "absent",
+		},
+		"null with null value": {
+			enforced: ruleset.EnforceChange{
+				Null: boolPointer(true),
+			},
+			value:    nil,
+			expected: true,
+		},
+		"null with non-null value": {
+			enforced: ruleset.EnforceChange{
+				Null: boolPointer(true),
+			},
+			value:           false,
+			expected:        false,
+			expectedFailure: "null",
+		},
+		"not null with null value": {
+			enforced: ruleset.EnforceChange{
+				Null: boolPointer(false),
+			},
+			value:           nil,
+			expected:        false,
+			expectedFailure: "not null",
+		},
+		"value and regex match": {
+			enforced: ruleset.EnforceChange{
+				Value:      "prod-app",
+				MatchRegex: "^prod-",
+			},
+			value:    "prod-app",
+			expected: true,
+		},
+		"value does not match": {
+			enforced: ruleset.EnforceChange{
+				Value:      "prod-app",
+				MatchRegex: "^prod-",
+			},
+			value:           "prod-db",
+			expected:        false,
+			expectedFailure: "prod-app",
+		},
+		"matchRegex matches": {
+			enforced: ruleset.EnforceChange{
+				MatchRegex: "^prod-[a-z0-9-]+$",
+			},
+			value:    "prod-bucket-1",
+			expected: true,
+		},
+		"matchRegex does not match": {
+			enforced: ruleset.EnforceChange{
+				MatchRegex: "^prod-[a-z0-9-]+$",
+			},
+			value:           "dev-bucket",
+			expected:        false,
+			expectedFailure: "matches regex ^prod-[a-z0-9-]+$",
+		},
+		"matchRegex with non-string value": {
+			enforced: ruleset.EnforceChange{
+				MatchRegex: "^[0-9]+$",
+			},
+			value:    10,
+			expected: true,
+		},
+		"notMatchRegex matches": {
+			enforced: ruleset.EnforceChange{
+				NotMatchRegex: "public",
+			},
+			value:    "private",
+			expected: true,
+		},
+		"notMatchRegex does not match": {
+			enforced: ruleset.EnforceChange{
+				NotMatchRegex: "public",
+			},
+			value:           "public-read",
+			expected:        false,
+			expectedFailure: "does not match regex public",
+		},
+		"invalid regex": {
+			enforced: ruleset.EnforceChange{
+				MatchRegex: "(",
+			},
+			value:           "value",
+			expected:        false,
+			expectedFailure: "matches regex ( (invalid regex: error parsing regexp: missing closing ): `(`)",
+		},
+		"min and max with string number": {
+			enforced: ruleset.EnforceChange{
+				Min: floatPointer(1),
+				Max: floatPointer(50),
+			},
+			value:    "50",
+			expected: true,
+		},
+		"max with float number": {
+			enforced: ruleset.EnforceChange{
+				Min: floatPointer(1),
+				Max: floatPointer(50),
+			},
+			value:           float64(51),
+			expected:        false,
+			expectedFailure: "number >= 1 and <= 50",
+		},
+		"min with int number": {
+			enforced: ruleset.EnforceChange{
+				Min: floatPointer(1.5),
+			},
+			value:           1,
+			expected:        false,
+			expectedFailure: "number >= 1.5",
+		},
+		"greaterThan and lessThan": {
+			enforced: ruleset.EnforceChange{
+				GreaterThan: floatPointer(0),
+				LessThan:    floatPointer(10),
+			},
+			value:    "9.5",
+			expected: true,
+		},
+		"greaterThan is exclusive": {
+			enforced: ruleset.EnforceChange{
+				GreaterThan: floatPointer(0),
+			},
+			value:           float64(0),
+			expected:        false,
+			expectedFailure: "number > 0",
+		},
+		"lessThan is exclusive": {
+			enforced: ruleset.EnforceChange{
+				LessThan: floatPointer(10),
+			},
+			value:           "10",
+			expected:        false,
+			expectedFailure: "number < 10",
+		},
+		"number matcher with non-number": {
+			enforced: ruleset.EnforceChange{
+				Max: floatPointer(10),
+			},
+			value:           "ten",
+			expected:        false,
+			expectedFailure: "number <= 10 (not a number)",
+		},
+		"not matches": {
+			enforced: ruleset.EnforceChange{
+				Not: &ruleset.EnforceChange{
+					MatchAny: []interface{}{"public-read", "public-read-write"},
+				},
+			},
+			value:    "private",
+			expected: true,
+		},
+		"not does not match": {
+			enforced: ruleset.EnforceChange{
+				Not: &ruleset.EnforceChange{
+					MatchAny: []interface{}{"public-read", "public-read-write"},
+				},
+			},
+			value:           "public-read",
+			expected:        false,
+			expectedFailure: "not (one of: [public-read public-read-write])",
+		},
+		"not with multiple matchers": {
+			enforced: ruleset.EnforceChange{
+				Not: &ruleset.EnforceChange{
+					MatchRegex: "^p",
+					Min:        floatPointer(3),
+				},
+			},
+			value:    "p3",
+			expected: true,
+		},
+		"allOf matches": {
+			enforced: ruleset.EnforceChange{
+				AllOf: []ruleset.EnforceChange{
+					{MatchRegex: "^n1-"},
+					{Not: &ruleset.EnforceChange{MatchRegex: "highmem"}},
+				},
+			},
+			value:    "n1-standard-4",
+			expected: true,
+		},
+		"allOf reports failing branch": {
+			enforced: ruleset.EnforceChange{
+				AllOf: []ruleset.EnforceChange{
+					{MatchRegex: "^n1-"},
+					{Not: &ruleset.EnforceChange{MatchRegex: "highmem"}},
+				},
+			},
+			value:           "n1-highmem-4",
+			expected:        false,
+			expectedFailure: "all of: [1] not (matches regex highmem)",
+		},
+		"anyOf matches": {
+			enforced: ruleset.EnforceChange{
+				AnyOf: []ruleset.EnforceChange{
+					{Value: "t3.micro"},
+					{MatchRegex: "^m5\\."},
+				},
+			},
+			value:    "m5.large",
+			expected: true,
+		},
+		"anyOf reports every branch": {
+			enforced: ruleset.EnforceChange{
+				AnyOf: []ruleset.EnforceChange{
+					{Value: "t3.micro"},
+					{MatchRegex: "^m5\\."},
+				},
+			},
+			value:           "p3.2xlarge",
+			expected:        false,
+			expectedFailure: "any of: [0] t3.micro; [1] matches regex ^m5\\.",
+		},
+		"oneOf matches": {
+			enforced: ruleset.EnforceChange{
+				OneOf: []ruleset.EnforceChange{
+					{Max: floatPointer(10)},
+					{Min: floatPointer(100)},
+				},
+			},
+			value:    "5",
+			expected: true,
+		},
+		"oneOf with no match": {
+			enforced: ruleset.EnforceChange{
+				OneOf: []ruleset.EnforceChange{
+					{Max: floatPointer(10)},
+					{Min: floatPointer(100)},
+				},
+			},
+			value:           "50",
+			expected:        false,
+			expectedFailure: "exactly one of: [0] number <= 10; [1] number >= 100",
+		},
+		"oneOf with multiple matches": {
+			enforced: ruleset.EnforceChange{
+				OneOf: []ruleset.EnforceChange{
+					{Max: floatPointer(10)},
+					{Max: floatPointer(100)},
+				},
+			},
+			value:           "5",
+			expected:        false,
+			expectedFailure: "exactly one of, but [0] and [1] matched",
+		},
+		"not each with every element matching": {
+			enforced: ruleset.EnforceChange{
+				Not: &ruleset.EnforceChange{
+					Each: &ruleset.EnforceChange{
+						MatchRegex: "^10\\.",
+					},
+				},
+			},
+			value:           []interface{}{"10.0.0.0/8", "10.1.0.0/16"},
+			expected:        false,
+			expectedFailure: "not (each element matches)",
+		},
+		"anyOf with element matchers": {
+			enforced: ruleset.EnforceChange{
+				AnyOf: []ruleset.EnforceChange{
+					{Value: "all"},
+					{
+						Each: &ruleset.EnforceChange{
+							MatchRegex: "^10\\.",
+						},
+					},
+				},
+			},
+			value:           []interface{}{"10.0.0.0/8", "0.0.0.0/0"},
+			expected:        false,
+			expectedFailure: "any of: [0] all; [1] each element matches ([1]: matches regex ^10\\.)",
+		},
+		"unordered value in a different order": {
+			enforced: ruleset.EnforceChange{
+				Value:     []interface{}{"sg-1", "sg-2"},
+				Unordered: true,
+			},
+			value:    []interface{}{"sg-2", "sg-1"},
+			expected: true,
+		},
+		"unordered value with different duplicates": {
+			enforced: ruleset.EnforceChange{
+				Value:     []interface{}{"sg-1", "sg-1", "sg-2"},
+				Unordered: true,
+			},
+			value:           []interface{}{"sg-1", "sg-2", "sg-2"},
+			expected:        false,
+			expectedFailure: "[sg-1 sg-1 sg-2] (in any order)",
+		},
+		"unordered matchAny": {
+			enforced: ruleset.EnforceChange{
+				MatchAny: []interface{}{
+					[]interface{}{"us-east1-b", "us-east1-c"},
+				},
+				Unordered: true,
+			},
+			value:    []interface{}{"us-east1-c", "us-east1-b"},
+			expected: true,
+		},
+		"unordered value from a JSON plan": {
+			enforced: ruleset.EnforceChange{
+				Value:     []interface{}{443, 80},
+				Unordered: true,
+			},
+			value:    []interface{}{float64(80), float64(443)},
+			expected: true,
+		},
+		"unordered value from a text plan": {
+			enforced: ruleset.EnforceChange{
+				Value:     []interface{}{443, 80},
+				Unordered: true,
+			},
+			value:    []interface{}{"80", "443"},
+			expected: true,
+		},
+		"containsAll with numbers from a JSON plan": {
+			enforced: ruleset.EnforceChange{
+				ContainsAll: []interface{}{80, 443},
+			},
+			value:    []interface{}{float64(22), float64(80), float64(443)},
+			expected: true,
+		},
+		"containsAll with numbers from a text plan": {
+			enforced: ruleset.EnforceChange{
+				ContainsAll: []interface{}{80, 443},
+			},
+			value:    []interface{}{"22", "80", "443"},
+			expected: true,
+		},
+		"containsAny with numbers from a JSON plan": {
+			enforced: ruleset.EnforceChange{
+				ContainsAny: []interface{}{80, 443},
+			},
+			value:    []interface{}{float64(443)},
+			expected: true,
+		},
+		"subsetOf with numbers from a text plan": {
+			enforced: ruleset.EnforceChange{
+				SubsetOf: []interface{}{80, 443},
+			},
+			value:           []interface{}{"80", "8080"},
+			expected:        false,
+			expectedFailure: "subset of: [80 443]",
+		},
+		"containsAll does not compare strings as numbers": {
+			enforced: ruleset.EnforceChange{
+				ContainsAll: []interface{}{"1.0"},
+			},
+			value:           []interface{}{"1"},
+			expected:        false,
+			expectedFailure: "contains all of: [1.0]",
+		},
+		"containsAll with every element": {
+			enforced: ruleset.EnforceChange{
+				ContainsAll: []interface{}{"sg-1", "sg-2"},
+			},
+			value:    []interface{}{"sg-3", "sg-2", "sg-1"},
+			expected: true,
+		},
+		"containsAll with missing element": {
+			enforced: ruleset.EnforceChange{
+				ContainsAll: []interface{}{"sg-1", "sg-2"},
+			},
+			value:           []interface{}{"sg-1"},
+			expected:        false,
+			expectedFailure: "contains all of: [sg-1 sg-2]",
+		},
+		"containsAny with one element": {
+			enforced: ruleset.EnforceChange{
+				ContainsAny: []interface{}{"sg-1", "sg-2"},
+			},
+			value:    []interface{}{"sg-3", "sg-2"},
+			expected: true,
+		},
+		"containsAny with no elements": {
+			enforced: ruleset.EnforceChange{
+				ContainsAny: []interface{}{"sg-1", "sg-2"},
+			},
+			value:           []interface{}{},
+			expected:        false,
+			expectedFailure: "contains any of: [sg-1 sg-2]",
+		},
+		"subsetOf with allowed elements": {
+			enforced: ruleset.EnforceChange{
+				SubsetOf: []interface{}{"us-east1-b", "us-east1-c", "us-east1-d"},
+			},
+			value:    []interface{}{"us-east1-d", "us-east1-b"},
+			expected: true,
+		},
+		"subsetOf with disallowed element": {
+			enforced: ruleset.EnforceChange{
+				SubsetOf: []interface{}{"us-east1-b", "us-east1-c"},
+			},
+			value:           []interface{}{"us-east1-b", "europe-west1-b"},
+			expected:        false,
+			expectedFailure: "subset of: [us-east1-b us-east1-c]",
+		},
+		"subsetOf with a value that is not a list": {
+			enforced: ruleset.EnforceChange{
+				SubsetOf: []interface{}{"us-east1-b"},
+			},
+			value:           "us-east1-b",
+			expected:        false,
+			expectedFailure: "subset of: [us-east1-b] (not a list)",
+		},
+		"hasPrefix with matching string": {
+			enforced: ruleset.EnforceChange{
+				HasPrefix: "arn:aws:iam::",
+			},
+			value:    "arn:aws:iam::123456789012:role/app",
+			expected: true,
+		},
+		"hasPrefix with non-matching string": {
+			enforced: ruleset.EnforceChange{
+				HasPrefix: "arn:aws:iam::",
+			},
+			value:           "arn:aws:s3:::bucket",
+			expected:        false,
+			expectedFailure: "has prefix \"arn:aws:iam::\"",
+		},
+		"hasSuffix with matching string": {
+			enforced: ruleset.EnforceChange{
+				HasSuffix: ".example.com",
+			},
+			value:    "api.example.com",
+			expected: true,
+		},
+		"contains with non-matching string": {
+			enforced: ruleset.EnforceChange{
+				Contains: "registry.example.com/",
+			},
+			value:           "docker.io/nginx",
+			expected:        false,
+			expectedFailure: "contains \"registry.example.com/\"",
+		},
+		"equalFold with different case": {
+			enforced: ruleset.EnforceChange{
+				EqualFold: "US-EAST1",
+			},
+			value:    "us-east1",
+			expected: true,
+		},
+		"string matcher with a value that is not a string": {
+			enforced: ruleset.EnforceChange{
+				HasPrefix: "1",
+			},
+			value:           float64(10),
+			expected:        false,
+			expectedFailure: "has prefix \"1\" (not a string)",
+		},
+		"combined string matchers": {
+			enforced: ruleset.EnforceChange{
+				HasPrefix: "prod-",
+				HasSuffix: "-bucket",
+			},
+			value:           "prod-logs",
+			expected:        false,
+			expectedFailure: "has suffix \"-bucket\"",
+		},
+		"withinCIDR with subnet inside range": {
+			enforced: ruleset.EnforceChange{
+				WithinCIDR: []string{"10.0.0.0/8"},
+			},
+			value:    "10.1.0.0/16",
+			expected: true,
+		},
+		"withinCIDR with range larger than allowed": {
+			enforced: ruleset.EnforceChange{
+				WithinCIDR: []string{"10.0.0.0/16"},
+			},
+			value:           "10.0.0.0/8",
+			expected:        false,
+			expectedFailure: "within CIDR [10.0.0.0/16] (10.0.0.0/8 does not match)",
+		},
+		"withinCIDR with list inside any range": {
+			enforced: ruleset.EnforceChange{
+				WithinCIDR: []string{"10.0.0.0/8", "fd00::/8"},
+			},
+			value:    []interface{}{"10.2.0.0/24", "fd12:3456::/64"},
+			expected: true,
+		},
+		"withinCIDR with list element outside ranges": {
+			enforced: ruleset.EnforceChange{
+				WithinCIDR: []string{"10.0.0.0/8"},
+			},
+			value:           []interface{}{"10.2.0.0/24", "0.0.0.0/0"},
+			expected:        false,
+			expectedFailure: "within CIDR [10.0.0.0/8] (0.0.0.0/0 does not match)",
+		},
+		"withinCIDR with invalid CIDR value": {
+			enforced: ruleset.EnforceChange{
+				WithinCIDR: []string{"10.0.0.0/8"},
+			},
+			value:           "10.0.0.1",
+			expected:        false,
+			expectedFailure: "within CIDR [10.0.0.0/8] (10.0.0.1 is not a CIDR)",
+		},
+		"withinCIDR with IPv6 value and IPv4 range": {
+			enforced: ruleset.EnforceChange{
+				WithinCIDR: []string{"0.0.0.0/0"},
+			},
+			value:           "::/0",
+			expected:        false,
+			expectedFailure: "within CIDR [0.0.0.0/0] (::/0 does not match)",
+		},
+		"notOverlapCIDR with separate range": {
+			enforced: ruleset.EnforceChange{
+				NotOverlapCIDR: []string{"10.0.0.0/16"},
+			},
+			value:    "10.1.0.0/16",
+			expected: true,
+		},
+		"notOverlapCIDR with overlapping range": {
+			enforced: ruleset.EnforceChange{
+				NotOverlapCIDR: []string{"10.0.1.0/24"},
+			},
+			value:           []interface{}{"10.0.0.0/16"},
+			expected:        false,
+			expectedFailure: "not overlapping CIDR [10.0.1.0/24] (10.0.0.0/16 does not match)",
+		},
+		"versionConstraint with version in range": {
+			enforced: ruleset.EnforceChange{
+				VersionConstraint: ">= 1.27, < 1.30",
+			},
+			value:    "1.28.5",
+			expected: true,
+		},
+		"versionConstraint with version out of range": {
+			enforced: ruleset.EnforceChange{
+				VersionConstraint: ">= 1.27, < 1.30",
+			},
+			value:           "1.30.1",
+			expected:        false,
+			expectedFailure: "version >= 1.27, < 1.30",
+		},
+		"versionConstraint with provider suffix": {
+			enforced: ruleset.EnforceChange{
+				VersionConstraint: ">= 1.27, < 1.30",
+			},
+			value:    "1.27.3-gke.100",
+			expected: true,
+		},
+		"versionConstraint with a value that is not a version": {
+			enforced: ruleset.EnforceChange{
+				VersionConstraint: ">= 1.27",
+			},
+			value:           "latest",
+			expected:        false,
+			expectedFailure: "version >= 1.27 (not a version)",
+		},
+		"not json with matching document": {
+			enforced: ruleset.EnforceChange{
+				Not: &ruleset.EnforceChange{
+					JSON: &ruleset.EnforceChange{
+						EnforceChange: map[string]ruleset.EnforceChange{
+							"Principal": {
+								Value: "*",
+							},
+						},
+					},
+				},
+			},
+			value:           map[string]interface{}{"Principal": "*"},
+			expected:        false,
+			expectedFailure: "not (JSON document matches)",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			failure, got := matchValue(tc.enforced, "", &checkOptions{}, tc.value)
+			if got != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+			if failure != tc.expectedFailure {
+				t.Errorf("Expected failure: %v but got %v", tc.expectedFailure, failure)
+			}
+		})
+	}
+}
+
+func TestMatchRelational(t *testing.T) {
+	cases := map[string]struct {
+		enforced        ruleset.EnforceChange
+		previous        interface{}
+		hasPrevious     bool
+		value           interface{}
+		expected        bool
+		expectedFailure string
+	}{
+		"unchanged with equal value": {
+			enforced: ruleset.EnforceChange{
+				Unchanged: true,
+			},
+			previous:    "us-east1",
+			hasPrevious: true,
+			value:       "us-east1",
+			expected:    true,
+		},
+		"unchanged with different value": {
+			enforced: ruleset.EnforceChange{
+				Unchanged: true,
+			},
+			previous:        "us-east1",
+			hasPrevious:     true,
+			value:           "us-west1",
+			expected:        false,
+			expectedFailure: "unchanged from us-east1",
+		},
+		"decrease only with larger value": {
+			enforced: ruleset.EnforceChange{
+				DecreaseOnly: true,
+			},
+			previous:        float64(10),
+			hasPrevious:     true,
+			value:           float64(11),
+			expected:        false,
+			expectedFailure: "decrease only from 10",
+		},
+		"max delta within range": {
+			enforced: ruleset.EnforceChange{
+				MaxDelta: floatPointer(2),
+			},
+			previous:    float64(3),
+			hasPrevious: true,
+			value:       float64(1),
+			expected:    true,
+		},
+		"max percent change within range": {
+			enforced: ruleset.EnforceChange{
+				MaxPercentChange: floatPointer(20),
+			},
+			previous:    "100",
+			hasPrevious: true,
+			value:       "120",
+			expected:    true,
+		},
+		"max percent change out of range": {
+			enforced: ruleset.EnforceChange{
+				MaxPercentChange: floatPointer(20),
+			},
+			previous:        float64(100),
+			hasPrevious:     true,
+			value:           float64(79),
+			expected:        false,
+			expectedFailure: "change of at most 20% from 100",
+		},
+		"max percent change from zero": {
+			enforced: ruleset.EnforceChange{
+				MaxPercentChange: floatPointer(20),
+			},
+			previous:        float64(0),
+			hasPrevious:     true,
+			value:           float64(1),
+			expected:        false,
+			expectedFailure: "change of at most 20% from 0",
+		},
+		"increase only without previous value": {
+			enforced: ruleset.EnforceChange{
+				IncreaseOnly: true,
+			},
+			value:           float64(1),
+			expected:        false,
+			expectedFailure: "increase only (no previous value)",
+		},
+		"increase only with previous value that is not a number": {
+			enforced: ruleset.EnforceChange{
+				IncreaseOnly: true,
+			},
+			previous:        "large",
+			hasPrevious:     true,
+			value:           float64(1),
+			expected:        false,
+			expectedFailure: "increase only from large (previous value is not a number)",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			failure, got := matchRelational(tc.enforced, tc.previous, tc.hasPrevious, tc.value)
+			if got != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+			if !got && failure != tc.expectedFailure {
+				t.Errorf("Expected failure: %v but got %v", tc.expectedFailure, failure)
+			}
+		})
+	}
+}
+
+func TestMatchPreviousComputed(t *testing.T) {
+	opts := &checkOptions{
+		Previous: map[string]interface{}{"size": float64(5)},
+		Computed: map[string]interface{}{"size": true},
+	}
+
+	failure, got := matchPrevious(ruleset.EnforceChange{IncreaseOnly: true}, "size", opts, "(known after apply)")
+	if !got {
+		t.Errorf("Expected computed value to pass but got failure %v", failure)
+	}
+	if failure != "increase only (unknown)" {
+		t.Errorf("Expected description: increase only (unknown) but got %v", failure)
+	}
+}
+
+func TestMatchAttributes(t *testing.T) {
+	values := map[string]interface{}{
+		"region":         "us-east1",
+		"replica_region": "us-east1",
+		"min_size":       float64(2),
+		"max_size":       float64(1),
+		"self_link":      "(known after apply)",
+		"autoscaling": []interface{}{
+			map[string]interface{}{
+				"min_node_count": "3",
+			},
+		},
+	}
+	computed := map[string]interface{}{
+		"self_link": "(known after apply)",
+		"network":   true,
+	}
+
+	cases := map[string]struct {
+		enforced        ruleset.EnforceChange
+		key             string
+		expected        bool
+		expectedFailure string
+	}{
+		"equalsAttr with equal value": {
+			enforced: ruleset.EnforceChange{
+				EqualsAttr: "region",
+			},
+			key:      "replica_region",
+			expected: true,
+		},
+		"notEqualsAttr with equal value": {
+			enforced: ruleset.EnforceChange{
+				NotEqualsAttr: "region",
+			},
+			key:             "replica_region",
+			expected:        false,
+			expectedFailure: "not equal to attribute region (us-east1)",
+		},
+		"minAttr with smaller value": {
+			enforced: ruleset.EnforceChange{
+				MinAttr: "min_size",
+			},
+			key:             "max_size",
+			expected:        false,
+			expectedFailure: ">= attribute min_size (2)",
+		},
+		"maxAttr with nested attribute": {
+			enforced: ruleset.EnforceChange{
+				MaxAttr: "autoscaling[0].min_node_count",
+			},
+			key:      "min_size",
+			expected: true,
+		},
+		"greaterThanAttr with computed attribute": {
+			enforced: ruleset.EnforceChange{
+				GreaterThanAttr: "network.id",
+			},
+			key:      "min_size",
+			expected: true,
+		},
+		"equalsAttr with computed value": {
+			enforced: ruleset.EnforceChange{
+				EqualsAttr: "region",
+			},
+			key:      "self_link",
+			expected: true,
+		},
+		"equalsAttr with missing attribute": {
+			enforced: ruleset.EnforceChange{
+				EqualsAttr: "zone",
+			},
+			key:             "region",
+			expected:        false,
+			expectedFailure: "equal to attribute zone (attribute not present)",
+		},
+		"lessThanAttr with attribute that is not a number": {
+			enforced: ruleset.EnforceChange{
+				LessThanAttr: "region",
+			},
+			key:             "min_size",
+			expected:        false,
+			expectedFailure: "< attribute region (us-east1) (attribute is not a number)",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			opts := &checkOptions{
+				Values:   values,
+				Computed: computed,
+			}
+			failure, got := matchAttributes(tc.enforced, opts, tc.key, values[tc.key])
+			if got != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+			if !got && failure != tc.expectedFailure {
+				t.Errorf("Expected failure: %v but got %v", tc.expectedFailure, failure)
+			}
+		})
+	}
+}
+
+// TestMatchValueNestedInCombinators verifies combinators check attribute, relational and nested rules in their branches
+func TestMatchValueNestedInCombinators(t *testing.T) {
+	values := map[string]interface{}{
+		"region":         "eu",
+		"replica_region": "us",
+		"size":           float64(1),
+		"tags": map[string]interface{}{
+			"Env": "dev",
+		},
+	}
+	previous := map[string]interface{}{
+		"size": float64(5),
+	}
+
+	cases := map[string]struct {
+		enforced        ruleset.EnforceChange
+		key             string
+		expected        bool
+		expectedFailure string
+	}{
+		"anyOf with attribute matcher": {
+			enforced: ruleset.EnforceChange{
+				AnyOf: []ruleset.EnforceChange{
+					{EqualsAttr: "region"},
+				},
+			},
+			key:             "replica_region",
+			expected:        false,
+			expectedFailure: "any of: [0] equal to attribute region (eu)",
+		},
+		"anyOf with relational matcher": {
+			enforced: ruleset.EnforceChange{
+				AnyOf: []ruleset.EnforceChange{
+					{IncreaseOnly: true},
+				},
+			},
+			key:             "size",
+			expected:        false,
+			expectedFailure: "any of: [0] increase only from 5",
+		},
+		"allOf with nested rules": {
+			enforced: ruleset.EnforceChange{
+				AllOf: []ruleset.EnforceChange{
+					{
+						EnforceChange: map[string]ruleset.EnforceChange{
+							"Env": {Value: "prod"},
+						},
+					},
+				},
+			},
+			key:             "tags",
+			expected:        false,
+			expectedFailure: "all of: [0] rules for tags.Env (tags.Env: prod)",
+		},
+		"not with nested rules": {
+			enforced: ruleset.EnforceChange{
+				Not: &ruleset.EnforceChange{
+					EnforceChange: map[string]ruleset.EnforceChange{
+						"Env": {Value: "prod"},
+					},
+				},
+			},
+			key:      "tags",
+			expected: true,
+		},
+		"not with matching nested rules": {
+			enforced: ruleset.EnforceChange{
+				Not: &ruleset.EnforceChange{
+					EnforceChange: map[string]ruleset.EnforceChange{
+						"Env": {Value: "dev"},
+					},
+				},
+			},
+			key:             "tags",
+			expected:        false,
+			expectedFailure: "not (rules for tags.Env)",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			opts := &checkOptions{
+				Values:   values,
+				Previous: previous,
+			}
+			failure, got := matchValue(tc.enforced, tc.key, opts, values[tc.key])
+			if got != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+			if !got && failure != tc.expectedFailure {
+				t.Errorf("Expected failure: %v but got %v", tc.expectedFailure, failure)
+			}
+		})
+	}
+}
+
+func floatPointer(f float64) *float64 {
+	return &f
+}
+
+func TestCompileRegexCachesPattern(t *testing.T) {
+	first, err := compileRegex("^prod-[a-z0-9-]+$")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, err := compileRegex("^prod-[a-z0-9-]+$")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first != second {
+		t.Errorf("Expected the pattern to be compiled once")
+	}
+
+	if _, err := compileRegex("("); err == nil {
+		t.Errorf("Expected error for invalid regex")
+	}
+}
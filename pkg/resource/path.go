@@ -0,0 +1,181 @@
+package resource
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// joinPath appends the key to the dotted path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return fmt.Sprintf("%s.%s", path, key)
+}
+
+// indexPath appends the list index to the path
+func indexPath(path string, index int) string {
+	return fmt.Sprintf("%s[%d]", path, index)
+}
+
+// pathSegments splits a dotted path into its segments, treating list indexes as segments
+// Example: metadata[0].annotations -> [metadata 0 annotations]
+func pathSegments(path string) []string {
+	path = strings.ReplaceAll(path, "[", ".")
+	path = strings.ReplaceAll(path, "]", "")
+	return strings.Split(path, ".")
+}
+
+// matchSegments returns true if the path segments match the pattern segments
+// A "*" in the pattern matches any single segment
+func matchSegments(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i := range pattern {
+		if pattern[i] != "*" && pattern[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isIgnored returns true if the path matches any of the ignored patterns
+func isIgnored(ignored map[string]interface{}, path string) bool {
+	segments := pathSegments(path)
+	for pattern := range ignored {
+		if matchSegments(pathSegments(pattern), segments) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasIgnoredUnder returns true if any of the ignored patterns matches a path nested under the path
+func hasIgnoredUnder(ignored map[string]interface{}, path string) bool {
+	segments := pathSegments(path)
+	for pattern := range ignored {
+		patternSegments := pathSegments(pattern)
+		if len(patternSegments) > len(segments) && matchSegments(patternSegments[:len(segments)], segments) {
+			return true
+		}
+	}
+	return false
+}
+
+// missingIgnored returns the ignored patterns that did not match any of the ignored paths
+func missingIgnored(ignored, matched map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for pattern, v := range ignored {
+		patternSegments := pathSegments(pattern)
+		found := false
+		for path := range matched {
+			if matchSegments(patternSegments, pathSegments(path)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result[pattern] = v
+		}
+	}
+
+	return result
+}
+
+// pathDifference returns elements in A that do not have a path in B at or nested under them
+// only checks for key equality - ignores values
+func pathDifference(a, b map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for k, v := range a {
+		found := false
+		for path := range b {
+			if path == k || strings.HasPrefix(path, k+".") || strings.HasPrefix(path, k+"[") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
+// MatchPathPrefix returns true if the pattern matches the path, or a path the path is nested under
+// A "*" in the pattern matches any single segment
+func MatchPathPrefix(pattern, path string) bool {
+	patternSegments, segments := pathSegments(pattern), pathSegments(path)
+	if len(patternSegments) > len(segments) {
+		return false
+	}
+	return matchSegments(patternSegments, segments[:len(patternSegments)])
+}
+
+// LookupPath returns the value at the path, navigating nested maps and lists
+func LookupPath(values map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = values
+	for _, segment := range pathSegments(path) {
+		switch casted := current.(type) {
+		case map[string]interface{}:
+			v, ok := casted[segment]
+			if !ok {
+				return nil, false
+			}
+			current = v
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(casted) {
+				return nil, false
+			}
+			current = casted[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// IsComputed returns true if the value at the path is only known after apply
+// A computed parent, such as a block that is computed as a whole, makes every nested path computed
+func IsComputed(computed map[string]interface{}, path string) bool {
+	var current interface{} = computed
+	for _, segment := range pathSegments(path) {
+		switch casted := current.(type) {
+		case map[string]interface{}:
+			v, ok := casted[segment]
+			if !ok {
+				return false
+			}
+			current = v
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(casted) {
+				return false
+			}
+			current = casted[i]
+		default:
+			return isComputedValue(current)
+		}
+	}
+
+	return isComputedValue(current)
+}
+
+// isComputedValue returns true for the values of computed attributes
+// JSON plans mark computed attributes with true, while plans parsed from text keep the placeholder value
+func isComputedValue(v interface{}) bool {
+	switch casted := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return casted
+	case map[string]interface{}, []interface{}:
+		return false
+	default:
+		return true
+	}
+}
@@ -15,10 +15,9 @@ var (
 )
 
 type resource struct {
-	Name string
-	Type string
-	// TODO support Index
-	// Index interface{}
+	Name  string
+	Type  string
+	Index interface{}
 
 	Enforced map[string]ruleset.EnforceChange
 	Ignored  map[string]interface{}
@@ -35,6 +34,7 @@ func NewResourceFromConfig(resourceIdentifier ruleset.ResourceIdentifier, resour
 	return &resource{
 		Name:     resourceIdentifier.Name,
 		Type:     resourceIdentifier.Type,
+		Index:    resourceIdentifier.Index,
 		Enforced: resourceRules.Enforced,
 		Ignored:  ignored,
 
@@ -43,17 +43,23 @@ func NewResourceFromConfig(resourceIdentifier ruleset.ResourceIdentifier, resour
 }
 
 func (r *resource) CompareResult(values map[string]interface{}) *CompareResult {
-	result := &CompareResult{
-		Enforced: make(map[string]interface{}),
-		Failed:   make(map[string]interface{}),
-		Ignored:  make(map[string]interface{}),
-		Extra:    make(map[string]interface{}),
-	}
+	return r.compareResult(values, ResourceValues{Values: values})
+}
 
-	result.checkValues(r.Enforced, r.Ignored, values, "")
+// compareResult compares the values, using the resource values for the relational and attribute matchers
+func (r *resource) compareResult(values map[string]interface{}, rv ResourceValues) *CompareResult {
+	result := newCompareResult()
+
+	opts := &checkOptions{
+		Ignored:  r.Ignored,
+		Previous: rv.Previous,
+		Values:   rv.Values,
+		Computed: rv.Computed,
+	}
+	result.checkValues(r.Enforced, opts, values, "")
 
-	result.MissingEnforced = setDifference(enforcedSetDifference(make(map[string]interface{}), "", r.Enforced, result.Enforced), result.Failed)
-	result.MissingIgnored = setDifference(setDifference(r.Ignored, result.Ignored), result.Failed)
+	result.MissingEnforced = pathDifference(enforcedSetDifference(make(map[string]interface{}), "", r.Enforced, result.Enforced), result.Failed)
+	result.MissingIgnored = setDifference(missingIgnored(r.Ignored, result.Ignored), result.Failed)
 
 	return result
 }
@@ -68,7 +74,7 @@ func (r *resource) Compare(rv ResourceValues) bool {
 	} else if !r.CompareOptions.IgnoreComputed {
 		values = rv.GetCombined()
 	}
-	cmp := r.CompareResult(values)
+	cmp := r.compareResult(values, rv)
 
 	if r.CompareOptions.EnforceAll && len(cmp.MissingEnforced) > 0 {
 		return false
@@ -94,7 +100,7 @@ func (r *resource) Diff(rv ResourceValues) string {
 	} else if !r.CompareOptions.IgnoreComputed {
 		values = rv.GetCombined()
 	}
-	cmp := r.CompareResult(values)
+	cmp := r.compareResult(values, rv)
 
 	if r.CompareOptions.EnforceAll && len(cmp.MissingEnforced) > 0 {
 		buf.WriteString(utils.Red("Missing enforced arguments:\n"))
@@ -161,7 +167,7 @@ func enforcedSetDifference(result map[string]interface{}, keyPrefix string, a ma
 		if keyPrefix != "" {
 			k = fmt.Sprintf("%s.%s", keyPrefix, k)
 		}
-		if v.EnforceChange != nil {
+		if len(v.EnforceChange) > 0 {
 			result = enforcedSetDifference(result, k, v.EnforceChange, b)
 		} else if _, ok := b[k]; !ok {
 			result[k] = v
@@ -256,6 +256,766 @@ func TestResourceCompareResult(t *testing.T) {
 				MissingIgnored:  map[string]interface{}{},
 			},
 		},
+		"enforced key without matchers exists": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"key": {},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"key": "value",
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{
+					"key": ruleset.EnforceChange{},
+				},
+				Failed:          map[string]interface{}{},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"missing key required to exist": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"kms_key_id": {
+						Exists: boolPointer(true),
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{},
+				Failed: map[string]interface{}{
+					"kms_key_id": FailedArg{
+						Expected: "present",
+						Actual:   "<absent>",
+					},
+				},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"missing key required to be absent": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"key": {
+						Exists: boolPointer(false),
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{
+					"key": ruleset.EnforceChange{
+						Exists: boolPointer(false),
+					},
+				},
+				Failed:          map[string]interface{}{},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"nested key required to not be null": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"key": {
+						EnforceChange: map[string]ruleset.EnforceChange{
+							"nested-key": {
+								Null: boolPointer(false),
+							},
+						},
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"key": map[string]interface{}{},
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{},
+				Failed: map[string]interface{}{
+					"key.nested-key": FailedArg{
+						Expected: "not null",
+						Actual:   "<absent>",
+					},
+				},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"nested ignored path": {
+			resource: &resource{
+				Ignored: map[string]interface{}{
+					"tags.LastModified": true,
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"tags": map[string]interface{}{
+					"LastModified": "today",
+					"Owner":        "team",
+				},
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{},
+				Failed:   map[string]interface{}{},
+				Ignored: map[string]interface{}{
+					"tags.LastModified": true,
+				},
+				Extra: map[string]interface{}{
+					"tags.Owner": true,
+				},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"wildcard ignored path in list": {
+			resource: &resource{
+				Ignored: map[string]interface{}{
+					"metadata.*.annotations": true,
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"metadata": []interface{}{
+					map[string]interface{}{
+						"annotations": map[string]interface{}{"a": "b"},
+						"name":        "name",
+					},
+					map[string]interface{}{
+						"annotations": map[string]interface{}{},
+					},
+				},
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{},
+				Failed:   map[string]interface{}{},
+				Ignored: map[string]interface{}{
+					"metadata[0].annotations": true,
+					"metadata[1].annotations": true,
+				},
+				Extra: map[string]interface{}{
+					"metadata[0].name": true,
+				},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"ignored path only at full path": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"spec": {
+						EnforceChange: map[string]ruleset.EnforceChange{
+							"name": {
+								Value: "value",
+							},
+						},
+					},
+				},
+				Ignored: map[string]interface{}{
+					"labels": true,
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"name":   "value",
+					"labels": "nested",
+				},
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{
+					"spec.name": ruleset.EnforceChange{
+						Value: "value",
+					},
+				},
+				Failed:  map[string]interface{}{},
+				Ignored: map[string]interface{}{},
+				Extra: map[string]interface{}{
+					"spec.labels": true,
+				},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored: map[string]interface{}{
+					"labels": true,
+				},
+			},
+		},
+		"missing nested ignored path": {
+			resource: &resource{
+				Ignored: map[string]interface{}{
+					"tags.LastModified": true,
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"tags": map[string]interface{}{
+					"Owner": "team",
+				},
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{},
+				Failed:   map[string]interface{}{},
+				Ignored:  map[string]interface{}{},
+				Extra: map[string]interface{}{
+					"tags.Owner": true,
+				},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored: map[string]interface{}{
+					"tags.LastModified": true,
+				},
+			},
+		},
+		"each element matches nested rules": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"ingress": {
+						Each: &ruleset.EnforceChange{
+							EnforceChange: map[string]ruleset.EnforceChange{
+								"cidr_blocks": {
+									Not: &ruleset.EnforceChange{
+										Value: []interface{}{"0.0.0.0/0"},
+									},
+								},
+							},
+						},
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"ingress": []interface{}{
+					map[string]interface{}{
+						"cidr_blocks": []interface{}{"10.0.0.0/8"},
+					},
+					map[string]interface{}{
+						"cidr_blocks": []interface{}{"0.0.0.0/0"},
+					},
+				},
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{
+					"ingress[0].cidr_blocks": ruleset.EnforceChange{
+						Not: &ruleset.EnforceChange{
+							Value: []interface{}{"0.0.0.0/0"},
+						},
+					},
+				},
+				Failed: map[string]interface{}{
+					"ingress[1].cidr_blocks": FailedArg{
+						Expected: "not ([0.0.0.0/0])",
+						Actual:   []interface{}{"0.0.0.0/0"},
+					},
+				},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"any element matches": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"container": {
+						Any: &ruleset.EnforceChange{
+							EnforceChange: map[string]ruleset.EnforceChange{
+								"image": {
+									MatchRegex: "^registry.example.com/",
+								},
+							},
+						},
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"container": []interface{}{
+					map[string]interface{}{
+						"image": "nginx",
+						"name":  "proxy",
+					},
+					map[string]interface{}{
+						"image": "registry.example.com/app",
+						"name":  "app",
+					},
+				},
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{
+					"container": ruleset.EnforceChange{
+						Any: &ruleset.EnforceChange{
+							EnforceChange: map[string]ruleset.EnforceChange{
+								"image": {
+									MatchRegex: "^registry.example.com/",
+								},
+							},
+						},
+					},
+				},
+				Failed:          map[string]interface{}{},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"no element matches any": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"container": {
+						Any: &ruleset.EnforceChange{
+							EnforceChange: map[string]ruleset.EnforceChange{
+								"image": {
+									MatchRegex: "^registry.example.com/",
+								},
+							},
+						},
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"container": []interface{}{
+					map[string]interface{}{
+						"image": "nginx",
+					},
+				},
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{},
+				Failed: map[string]interface{}{
+					"container": FailedArg{
+						Expected: "any element matching: container[0].image: matches regex ^registry.example.com/",
+						Actual: []interface{}{
+							map[string]interface{}{
+								"image": "nginx",
+							},
+						},
+					},
+				},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"none with matching element": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"cidr_blocks": {
+						None: &ruleset.EnforceChange{
+							Value: "0.0.0.0/0",
+						},
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"cidr_blocks": []interface{}{"10.0.0.0/8", "0.0.0.0/0"},
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{},
+				Failed: map[string]interface{}{
+					"cidr_blocks[1]": FailedArg{
+						Expected: "no element matching 0.0.0.0/0",
+						Actual:   "0.0.0.0/0",
+					},
+				},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"none with elements without the attribute": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"statement": {
+						None: &ruleset.EnforceChange{
+							EnforceChange: map[string]ruleset.EnforceChange{
+								"principal": {
+									Value: "*",
+								},
+							},
+						},
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"statement": []interface{}{
+					map[string]interface{}{
+						"action": "s3:GetObject",
+					},
+					map[string]interface{}{
+						"action":    "s3:PutObject",
+						"principal": "arn:aws:iam::123456789012:root",
+					},
+				},
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{
+					"statement": ruleset.EnforceChange{
+						None: &ruleset.EnforceChange{
+							EnforceChange: map[string]ruleset.EnforceChange{
+								"principal": {
+									Value: "*",
+								},
+							},
+						},
+					},
+				},
+				Failed:          map[string]interface{}{},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"any with elements without the attribute": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"container": {
+						Any: &ruleset.EnforceChange{
+							EnforceChange: map[string]ruleset.EnforceChange{
+								"image": {
+									HasPrefix: "registry.example.com/",
+								},
+							},
+						},
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"container": []interface{}{
+					map[string]interface{}{
+						"name": "app",
+					},
+				},
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{},
+				Failed: map[string]interface{}{
+					"container": FailedArg{
+						Expected: "any element matching: container[0].image: present",
+						Actual: []interface{}{
+							map[string]interface{}{
+								"name": "app",
+							},
+						},
+					},
+				},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"each does not record attributes without rules as extra": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"ingress": {
+						Each: &ruleset.EnforceChange{
+							EnforceChange: map[string]ruleset.EnforceChange{
+								"cidr_blocks": {
+									Value: []interface{}{"10.0.0.0/8"},
+								},
+							},
+						},
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"ingress": []interface{}{
+					map[string]interface{}{
+						"cidr_blocks": []interface{}{"10.0.0.0/8"},
+						"from_port":   443,
+					},
+				},
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{
+					"ingress": ruleset.EnforceChange{
+						Each: &ruleset.EnforceChange{
+							EnforceChange: map[string]ruleset.EnforceChange{
+								"cidr_blocks": {
+									Value: []interface{}{"10.0.0.0/8"},
+								},
+							},
+						},
+					},
+					"ingress[0].cidr_blocks": ruleset.EnforceChange{
+						Value: []interface{}{"10.0.0.0/8"},
+					},
+				},
+				Failed:          map[string]interface{}{},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"matchers and nested rules are both checked": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"tags": {
+						Exists: boolPointer(true),
+						EnforceChange: map[string]ruleset.EnforceChange{
+							"Env": {
+								Value: "prod",
+							},
+						},
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"tags": map[string]interface{}{
+					"Env": "dev",
+				},
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{},
+				Failed: map[string]interface{}{
+					"tags.Env": FailedArg{
+						Expected: "prod",
+						Actual:   "dev",
+					},
+				},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"each with nested rules on elements that are not maps": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"ingress": {
+						Each: &ruleset.EnforceChange{
+							EnforceChange: map[string]ruleset.EnforceChange{
+								"cidr_blocks": {
+									None: &ruleset.EnforceChange{
+										Value: "0.0.0.0/0",
+									},
+								},
+							},
+						},
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"ingress": []interface{}{"0.0.0.0/0", "10.0.0.0/8"},
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{},
+				Failed: map[string]interface{}{
+					"ingress[0]": FailedArg{
+						Expected: "map",
+						Actual:   "0.0.0.0/0",
+					},
+					"ingress[1]": FailedArg{
+						Expected: "map",
+						Actual:   "10.0.0.0/8",
+					},
+				},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"each on a value that is not a list": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"cidr_blocks": {
+						Each: &ruleset.EnforceChange{
+							Value: "10.0.0.0/8",
+						},
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"cidr_blocks": "10.0.0.0/8",
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{},
+				Failed: map[string]interface{}{
+					"cidr_blocks": FailedArg{
+						Expected: "list",
+						Actual:   "10.0.0.0/8",
+					},
+				},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"json document matches": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"policy": {
+						JSON: &ruleset.EnforceChange{
+							EnforceChange: map[string]ruleset.EnforceChange{
+								"Version": {
+									Value: "2012-10-17",
+								},
+							},
+						},
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"policy": `{"Version": "2012-10-17", "Statement": []}`,
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{
+					"policy": ruleset.EnforceChange{
+						JSON: &ruleset.EnforceChange{
+							EnforceChange: map[string]ruleset.EnforceChange{
+								"Version": {
+									Value: "2012-10-17",
+								},
+							},
+						},
+					},
+				},
+				Failed:          map[string]interface{}{},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"json document with wildcard action": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"policy": {
+						JSON: &ruleset.EnforceChange{
+							EnforceChange: map[string]ruleset.EnforceChange{
+								"Statement": {
+									Each: &ruleset.EnforceChange{
+										EnforceChange: map[string]ruleset.EnforceChange{
+											"Action": {
+												Not: &ruleset.EnforceChange{
+													AnyOf: []ruleset.EnforceChange{
+														{Value: "*"},
+														{ContainsAny: []interface{}{"*"}},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"policy": `{"Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"]}, {"Effect": "Allow", "Action": "*"}]}`,
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{},
+				Failed: map[string]interface{}{
+					"policy.Statement[1].Action": FailedArg{
+						Expected: "not (any of: [0] *)",
+						Actual:   "*",
+					},
+				},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"json document with wildcard principal": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"policy": {
+						JSON: &ruleset.EnforceChange{
+							EnforceChange: map[string]ruleset.EnforceChange{
+								"Statement": {
+									Each: &ruleset.EnforceChange{
+										EnforceChange: map[string]ruleset.EnforceChange{
+											"Principal": {
+												EnforceChange: map[string]ruleset.EnforceChange{
+													"AWS": {
+														Not: &ruleset.EnforceChange{
+															Value: "*",
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"policy": `{"Statement": [{"Principal": {"AWS": "arn:aws:iam::123456789012:root"}}, {"Principal": "*"}]}`,
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{},
+				Failed: map[string]interface{}{
+					"policy.Statement[1].Principal": FailedArg{
+						Expected: "map",
+						Actual:   "*",
+					},
+				},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
+		"json matcher with invalid json": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"policy": {
+						JSON: &ruleset.EnforceChange{},
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: map[string]interface{}{
+				"policy": "{",
+			},
+			expected: &CompareResult{
+				Enforced: map[string]interface{}{},
+				Failed: map[string]interface{}{
+					"policy": FailedArg{
+						Expected: "JSON document (unexpected end of JSON input)",
+						Actual:   "{",
+					},
+				},
+				Ignored:         map[string]interface{}{},
+				Extra:           map[string]interface{}{},
+				MissingEnforced: map[string]interface{}{},
+				MissingIgnored:  map[string]interface{}{},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -306,6 +1066,71 @@ func TestResourceCompare(t *testing.T) {
 			},
 			expected: false,
 		},
+		"attribute reference with nested value": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"autoscaling": {
+						EnforceChange: map[string]ruleset.EnforceChange{
+							"max_size": {
+								MinAttr: "autoscaling.min_size",
+							},
+						},
+					},
+				},
+				CompareOptions: &CompareOptions{
+					IgnoreExtraArgs: true,
+				},
+			},
+			values: ResourceValues{
+				Values: map[string]interface{}{
+					"autoscaling": map[string]interface{}{
+						"min_size": float64(3),
+						"max_size": float64(2),
+					},
+				},
+			},
+			expected: false,
+		},
+		"attribute reference with computed attribute": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"replica_region": {
+						NotEqualsAttr: "region",
+					},
+				},
+				CompareOptions: &CompareOptions{
+					IgnoreExtraArgs: true,
+				},
+			},
+			values: ResourceValues{
+				Values: map[string]interface{}{
+					"replica_region": "us-east1",
+				},
+				Computed: map[string]interface{}{
+					"region": true,
+				},
+			},
+			expected: true,
+		},
+		"relational matcher with previous value": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"size": {
+						IncreaseOnly: true,
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: ResourceValues{
+				Values: map[string]interface{}{
+					"size": float64(10),
+				},
+				Previous: map[string]interface{}{
+					"size": float64(20),
+				},
+			},
+			expected: false,
+		},
 		"extra value that is ignored": {
 			resource: &resource{
 				Enforced: map[string]ruleset.EnforceChange{
@@ -555,6 +1380,38 @@ func TestResourceCompare(t *testing.T) {
 			},
 			expected: false,
 		},
+		"enforced value matches regex": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"key": {
+						MatchRegex: "^prod-",
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: ResourceValues{
+				Values: map[string]interface{}{
+					"key": "prod-bucket",
+				},
+			},
+			expected: true,
+		},
+		"enforced value matches notMatchRegex": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"key": {
+						NotMatchRegex: "^prod-",
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: ResourceValues{
+				Values: map[string]interface{}{
+					"key": "prod-bucket",
+				},
+			},
+			expected: false,
+		},
 	}
 
 	for name, tc := range cases {
@@ -610,6 +1467,27 @@ func TestResourceDiff(t *testing.T) {
 				"- Actual:   value",
 			},
 		},
+		"string matcher does not match": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"image": {
+						HasPrefix: "registry.example.com/",
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: ResourceValues{
+				Values: map[string]interface{}{
+					"image": "nginx",
+				},
+			},
+			expected: []string{
+				"Failed arguments:",
+				"- image",
+				"+ Expected: has prefix \"registry.example.com/\"",
+				"- Actual:   nginx",
+			},
+		},
 		"extra value that is ignored": {
 			resource: &resource{
 				Enforced: map[string]ruleset.EnforceChange{
@@ -871,6 +1749,38 @@ func TestResourceDiff(t *testing.T) {
 			},
 			expected: []string{"one of: [value1 value2]"},
 		},
+		"enforced value does not match regex": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"key": {
+						MatchRegex: "^prod-[a-z0-9-]+$",
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: ResourceValues{
+				Values: map[string]interface{}{
+					"key": "dev-bucket",
+				},
+			},
+			expected: []string{"key", "matches regex ^prod-[a-z0-9-]+$", "dev-bucket"},
+		},
+		"enforced value exceeds max": {
+			resource: &resource{
+				Enforced: map[string]ruleset.EnforceChange{
+					"max_node_count": {
+						Max: floatPointer(50),
+					},
+				},
+				CompareOptions: &CompareOptions{},
+			},
+			values: ResourceValues{
+				Values: map[string]interface{}{
+					"max_node_count": "100",
+				},
+			},
+			expected: []string{"max_node_count", "number <= 50", "100"},
+		},
 	}
 
 	for name, tc := range cases {
@@ -953,3 +1863,7 @@ func TestSetDifference(t *testing.T) {
 		})
 	}
 }
+
+func boolPointer(b bool) *bool {
+	return &b
+}
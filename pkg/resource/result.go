@@ -2,10 +2,36 @@ package resource
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/drlau/akashi/pkg/ruleset"
 )
 
+func newCompareResult() *CompareResult {
+	return &CompareResult{
+		Enforced: make(map[string]interface{}),
+		Failed:   make(map[string]interface{}),
+		Ignored:  make(map[string]interface{}),
+		Extra:    make(map[string]interface{}),
+	}
+}
+
+// checkOptions holds the state shared while checking the values of a resource
+type checkOptions struct {
+	// Ignored is the set of ignored paths
+	Ignored map[string]interface{}
+
+	// Previous is the values before the change, used by the relational matchers of updated resources
+	Previous map[string]interface{}
+
+	// Values is every value of the resource, used by the attribute matchers
+	Values map[string]interface{}
+
+	// Computed is the values that are only known after apply
+	Computed map[string]interface{}
+}
+
 type CompareResult struct {
 	// args that had a matching EnforcedValue and are equal
 	Enforced map[string]interface{}
@@ -26,67 +52,334 @@ type CompareResult struct {
 	MissingIgnored map[string]interface{}
 }
 
-func (cr *CompareResult) checkValues(enforced map[string]ruleset.EnforceChange, ignored map[string]interface{}, values map[string]interface{}, keyPrefix string) {
+func (cr *CompareResult) checkValues(enforced map[string]ruleset.EnforceChange, opts *checkOptions, values map[string]interface{}, keyPrefix string) {
 	// Passed in the plan's values
 	// Iterate over each key/value
 	for k, v := range values {
-		key := k
-		if keyPrefix != "" {
-			key = fmt.Sprintf("%s.%s", keyPrefix, k)
-		}
+		key := joinPath(keyPrefix, k)
 		// If the key is ignored, record and continue
-		if _, ok := ignored[k]; ok {
-			// TODO: handle nested ignore
-			cr.Ignored[k] = true
+		if isIgnored(opts.Ignored, key) {
+			cr.Ignored[key] = true
 			continue
 		}
 		// If the key is enforced...
 		if enforced, ok := enforced[k]; ok {
-			switch {
-			case enforced.Value != nil:
-				// Verify the value is what is expected
-				if !equal(enforced.Value, v) {
-					// Not equal - record as failed
-					cr.Failed[key] = FailedArg{
-						Expected: enforced.Value,
-						Actual:   v,
-					}
-				} else {
-					// equal
-					cr.Enforced[key] = enforced
-				}
-			case enforced.MatchAny != nil:
-				found := false
-				for _, val := range enforced.MatchAny {
-					// Verify the value is what is expected
-					if equal(val, v) {
-						// equal
-						cr.Enforced[key] = enforced
-						found = true
-						break
-					}
-				}
-				if !found {
-					cr.Failed[key] = FailedArg{
-						Expected: fmt.Sprintf("one of: %v", enforced.MatchAny),
-						Actual:   v,
-						MatchAny: true,
-					}
-				}
-			case enforced.EnforceChange != nil:
-				casted, ok := values[k].(map[string]interface{})
-				if ok {
-					cr.checkValues(enforced.EnforceChange, ignored, casted, k)
-				} else {
-					// failed
-					fmt.Println("failed to cast - failed enforced")
-				}
-			default:
-				// TODO: Tests that key exists and that's it - intended?
+			cr.checkValue(key, enforced, opts, v)
+		} else {
+			cr.checkExtra(key, v, opts)
+		}
+	}
+
+	// Enforced keys missing from the plan only fail if they are required to exist
+	for k, enforced := range enforced {
+		if _, ok := values[k]; ok || (enforced.Exists == nil && enforced.Null == nil) {
+			continue
+		}
+
+		key := joinPath(keyPrefix, k)
+		if expected, ok := matchAbsent(enforced); !ok {
+			cr.Failed[key] = FailedArg{
+				Expected: expected,
+				Actual:   "<absent>",
+			}
+		} else {
+			cr.Enforced[key] = enforced
+		}
+	}
+}
+
+// checkValue checks the value against the enforced rules for the key
+func (cr *CompareResult) checkValue(key string, enforced ruleset.EnforceChange, opts *checkOptions, v interface{}) {
+	if len(enforced.EnforceChange) > 0 && hasValueMatchers(enforced) {
+		cr.checkNested(key, enforced, opts, v)
+		return
+	}
+	if hasAttributeMatchers(enforced) {
+		cr.checkAttributes(key, enforced, opts, v)
+		return
+	}
+	if hasRelationalMatchers(enforced) {
+		cr.checkRelational(key, enforced, opts, v)
+		return
+	}
+	if enforced.JSON != nil {
+		cr.checkJSON(key, enforced, opts, v)
+		return
+	}
+	if hasElementMatchers(enforced) {
+		cr.checkElements(key, enforced, opts, v)
+		return
+	}
+
+	switch {
+	case hasMatchers(enforced):
+		// Verify the value matches every matcher, including value and matchAny
+		if expected, ok := matchValue(enforced, key, opts, v); !ok {
+			cr.Failed[key] = FailedArg{
+				Expected: expected,
+				Actual:   v,
+			}
+		} else {
+			cr.Enforced[key] = enforced
+		}
+	case enforced.Value != nil:
+		// Verify the value is what is expected
+		if !equal(enforced.Value, v) {
+			// Not equal - record as failed
+			cr.Failed[key] = FailedArg{
+				Expected: enforced.Value,
+				Actual:   v,
+			}
+		} else {
+			// equal
+			cr.Enforced[key] = enforced
+		}
+	case enforced.MatchAny != nil:
+		found := false
+		for _, val := range enforced.MatchAny {
+			// Verify the value is what is expected
+			if equal(val, v) {
+				// equal
+				cr.Enforced[key] = enforced
+				found = true
+				break
 			}
+		}
+		if !found {
+			cr.Failed[key] = FailedArg{
+				Expected: fmt.Sprintf("one of: %v", enforced.MatchAny),
+				Actual:   v,
+				MatchAny: true,
+			}
+		}
+	case len(enforced.EnforceChange) > 0:
+		casted, ok := v.(map[string]interface{})
+		if ok {
+			cr.checkValues(enforced.EnforceChange, opts, casted, key)
 		} else {
-			cr.Extra[key] = true
+			// Rules for nested attributes can only match a map
+			cr.Failed[key] = FailedArg{
+				Expected: "map",
+				Actual:   v,
+			}
 		}
+	default:
+		// No matchers, so the key only needs to exist
+		cr.Enforced[key] = enforced
+	}
+}
+
+// checkNested checks the matchers against the value, then the rules for its nested attributes
+func (cr *CompareResult) checkNested(key string, enforced ruleset.EnforceChange, opts *checkOptions, v interface{}) {
+	nested := enforced.EnforceChange
+	enforced.EnforceChange = nil
+	cr.checkValue(key, enforced, opts, v)
+	if _, ok := cr.Failed[key]; ok {
+		return
+	}
+
+	// Like rules with only nested attributes, the nested attributes are recorded instead of the key
+	delete(cr.Enforced, key)
+	cr.checkValue(key, ruleset.EnforceChange{EnforceChange: nested}, opts, v)
+}
+
+// checkElements checks the each, any and none rules against the elements of a list
+// Every other matcher is checked against the whole list first
+// Failures of each and none are recorded by element index, for example ingress[3].cidr_blocks
+func (cr *CompareResult) checkElements(key string, enforced ruleset.EnforceChange, opts *checkOptions, v interface{}) {
+	if expected, ok := matchValue(withoutElementMatchers(enforced), key, opts, v); !ok {
+		cr.Failed[key] = FailedArg{
+			Expected: expected,
+			Actual:   v,
+		}
+		return
+	}
+
+	elements, ok := v.([]interface{})
+	if !ok {
+		cr.Failed[key] = FailedArg{
+			Expected: "list",
+			Actual:   v,
+		}
+		return
+	}
+
+	failed := len(cr.Failed)
+	if enforced.Each != nil {
+		// Like JSON documents, attributes of the elements without rules are not recorded as extra
+		result := newCompareResult()
+		for i, element := range elements {
+			result.checkValue(indexPath(key, i), *enforced.Each, opts, element)
+		}
+		for k, e := range result.Enforced {
+			cr.Enforced[k] = e
+		}
+		for k, f := range result.Failed {
+			cr.Failed[k] = f
+		}
+		for k, i := range result.Ignored {
+			cr.Ignored[k] = i
+		}
+	}
+	if enforced.Any != nil {
+		var failures []string
+		found := false
+		for i, element := range elements {
+			elementFailed := checkElement(indexPath(key, i), *enforced.Any, opts, element)
+			if len(elementFailed) == 0 {
+				found = true
+				break
+			}
+			failures = append(failures, formatFailures(elementFailed))
+		}
+		if !found {
+			cr.Failed[key] = FailedArg{
+				Expected: fmt.Sprintf("any element matching: %s", strings.Join(failures, "; ")),
+				Actual:   v,
+			}
+		}
+	}
+	if enforced.None != nil {
+		for i, element := range elements {
+			if len(checkElement(indexPath(key, i), *enforced.None, opts, element)) == 0 {
+				cr.Failed[indexPath(key, i)] = FailedArg{
+					Expected: describeNone(*enforced.None, indexPath(key, i), opts, element),
+					Actual:   element,
+				}
+			}
+		}
+	}
+
+	if len(cr.Failed) == failed {
+		cr.Enforced[key] = enforced
+	}
+}
+
+// checkAttributes checks the attribute matchers against the other attributes of the resource, then every other matcher
+func (cr *CompareResult) checkAttributes(key string, enforced ruleset.EnforceChange, opts *checkOptions, v interface{}) {
+	if expected, ok := matchAttributes(enforced, opts, key, v); !ok {
+		cr.Failed[key] = FailedArg{
+			Expected: expected,
+			Actual:   v,
+		}
+		return
+	}
+
+	cr.checkValue(key, withoutAttributeMatchers(enforced), opts, v)
+	if _, ok := cr.Enforced[key]; ok {
+		cr.Enforced[key] = enforced
+	}
+}
+
+// checkRelational checks the relational matchers against the value before the change, then every other matcher
+func (cr *CompareResult) checkRelational(key string, enforced ruleset.EnforceChange, opts *checkOptions, v interface{}) {
+	if expected, ok := matchPrevious(enforced, key, opts, v); !ok {
+		cr.Failed[key] = FailedArg{
+			Expected: expected,
+			Actual:   v,
+		}
+		return
+	}
+
+	cr.checkValue(key, withoutRelationalMatchers(enforced), opts, v)
+	if _, ok := cr.Enforced[key]; ok {
+		cr.Enforced[key] = enforced
+	}
+}
+
+// checkJSON decodes the JSON string and checks the decoded document against the nested rules
+// Every other matcher is checked against the string first
+// Failures are recorded by their path in the document, for example policy.Statement[1].Action
+// Keys in the document without rules are not recorded as extra
+func (cr *CompareResult) checkJSON(key string, enforced ruleset.EnforceChange, opts *checkOptions, v interface{}) {
+	others := enforced
+	others.JSON = nil
+	if expected, ok := matchValue(others, key, opts, v); !ok {
+		cr.Failed[key] = FailedArg{
+			Expected: expected,
+			Actual:   v,
+		}
+		return
+	}
+
+	document, err := decodeJSON(v)
+	if err != nil {
+		cr.Failed[key] = FailedArg{
+			Expected: fmt.Sprintf("JSON document (%v)", err),
+			Actual:   v,
+		}
+		return
+	}
+
+	result := newCompareResult()
+	result.checkValue(key, *enforced.JSON, opts, document)
+	for k, f := range result.Failed {
+		cr.Failed[k] = f
+	}
+	if len(result.Failed) == 0 {
+		cr.Enforced[key] = enforced
+	}
+}
+
+// checkElement checks a single element against the nested rules and returns the failures
+// Nested attributes missing from the element are failures, so an element only matches if every nested rule was checked
+func checkElement(key string, enforced ruleset.EnforceChange, opts *checkOptions, v interface{}) map[string]interface{} {
+	cr := newCompareResult()
+	cr.checkValue(key, enforced, opts, v)
+	if len(cr.Failed) == 0 {
+		cr.checkUnchecked(key, enforced.EnforceChange)
+	}
+
+	return cr.Failed
+}
+
+// checkUnchecked records the nested attributes that were neither enforced nor failed as failures
+func (cr *CompareResult) checkUnchecked(keyPrefix string, enforced map[string]ruleset.EnforceChange) {
+	for k, e := range enforced {
+		key := joinPath(keyPrefix, k)
+		if _, ok := cr.Enforced[key]; ok {
+			continue
+		}
+		if _, ok := cr.Failed[key]; ok {
+			continue
+		}
+		if len(e.EnforceChange) > 0 {
+			// Rules with nested attributes record the nested attributes instead of the key
+			cr.checkUnchecked(key, e.EnforceChange)
+			continue
+		}
+
+		cr.Failed[key] = FailedArg{
+			Expected: describeExists(true),
+			Actual:   "<absent>",
+		}
+	}
+}
+
+// checkExtra records a value without an enforced value as extra, unless it is ignored
+// Maps and lists with ignored paths nested under them are checked element by element,
+// so only the ignored paths are skipped
+func (cr *CompareResult) checkExtra(key string, v interface{}, opts *checkOptions) {
+	if isIgnored(opts.Ignored, key) {
+		cr.Ignored[key] = true
+		return
+	}
+	if !hasIgnoredUnder(opts.Ignored, key) {
+		cr.Extra[key] = true
+		return
+	}
+
+	switch casted := v.(type) {
+	case map[string]interface{}:
+		for k, nested := range casted {
+			cr.checkExtra(joinPath(key, k), nested, opts)
+		}
+	case []interface{}:
+		for i, nested := range casted {
+			cr.checkExtra(indexPath(key, i), nested, opts)
+		}
+	default:
+		cr.Extra[key] = true
 	}
 }
 
@@ -114,6 +407,23 @@ func (cr *CompareResult) GetMissingIgnored() map[string]interface{} {
 	return cr.MissingIgnored
 }
 
+// formatFailures formats the failures in order of their keys
+func formatFailures(failed map[string]interface{}) string {
+	keys := make([]string, 0, len(failed))
+	for k := range failed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var failures []string
+	for _, k := range keys {
+		if f, ok := failed[k].(FailedArg); ok {
+			failures = append(failures, fmt.Sprintf("%s: %v", k, f.Expected))
+		}
+	}
+	return strings.Join(failures, ", ")
+}
+
 type FailedArg struct {
 	Expected interface{}
 	Actual   interface{}
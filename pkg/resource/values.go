@@ -5,6 +5,8 @@ type ResourceValues struct {
 	// TODO: better implementation of ChangedValues(a filter operation on Values seems ideal)
 	ChangedValues map[string]interface{}
 	Computed      map[string]interface{}
+	// Previous is the values before the change, used by the relational matchers of updated resources
+	Previous map[string]interface{}
 }
 
 // TODO: fix merging maps
@@ -0,0 +1,46 @@
+package ruleset
+
+import "fmt"
+
+// Budget is the maximum number of resources with a planned action
+type Budget struct {
+	// Type limits the budget to resources of the type
+	// If empty, every resource counts towards the budget
+	Type string `yaml:"type,omitempty"`
+
+	// Count is the maximum number of resources
+	Count *int `yaml:"count"`
+}
+
+// String describes the resources counted by the budget
+func (b Budget) String() string {
+	if b.Type == "" {
+		return "all resources"
+	}
+	return b.Type
+}
+
+// Budgets is a list of budgets
+// In YAML, it is either a count, a single budget or a list of budgets
+type Budgets []Budget
+
+func (b *Budgets) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var count int
+	if err := unmarshal(&count); err == nil {
+		*b = Budgets{{Count: &count}}
+		return nil
+	}
+
+	var budget Budget
+	if err := unmarshal(&budget); err == nil {
+		*b = Budgets{budget}
+		return nil
+	}
+
+	var budgets []Budget
+	if err := unmarshal(&budgets); err != nil {
+		return fmt.Errorf("budget must be a count, a budget or a list of budgets")
+	}
+	*b = budgets
+	return nil
+}
@@ -0,0 +1,66 @@
+package ruleset
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func intPointer(i int) *int {
+	return &i
+}
+
+func TestBudgetsUnmarshalYAML(t *testing.T) {
+	cases := map[string]struct {
+		in          string
+		expected    Budgets
+		expectedErr bool
+	}{
+		"count": {
+			in:       "maxDestroyed: 0",
+			expected: Budgets{{Count: intPointer(0)}},
+		},
+		"budget": {
+			in: "maxDestroyed: {type: aws_db_instance, count: 0}",
+			expected: Budgets{
+				{Type: "aws_db_instance", Count: intPointer(0)},
+			},
+		},
+		"list of budgets": {
+			in: `
+maxDestroyed:
+  - count: 5
+  - type: aws_db_instance
+    count: 0
+`,
+			expected: Budgets{
+				{Count: intPointer(5)},
+				{Type: "aws_db_instance", Count: intPointer(0)},
+			},
+		},
+		"invalid": {
+			in:          "maxDestroyed: many",
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var rs Ruleset
+			err := yaml.Unmarshal([]byte(tc.in), &rs)
+			if tc.expectedErr {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(rs.MaxDestroyed, tc.expected); diff != "" {
+				t.Errorf("(-got, +expected)\n%s", diff)
+			}
+		})
+	}
+}
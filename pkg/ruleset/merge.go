@@ -0,0 +1,202 @@
+package ruleset
+
+import (
+	"fmt"
+)
+
+// Merge combines two rulesets
+// Strict and requireName are enabled if enabled in either ruleset, and resources, plan rules and budgets are appended
+// Differing matchMode or default options, and rules for the same resource, are reported as conflicts
+func Merge(a, b Ruleset) (Ruleset, error) {
+	var (
+		result = Ruleset{
+			Include:      append(append([]string(nil), a.Include...), b.Include...),
+			PlanRules:    append(append([]PlanRule(nil), a.PlanRules...), b.PlanRules...),
+			MaxCreated:   append(append(Budgets(nil), a.MaxCreated...), b.MaxCreated...),
+			MaxDestroyed: append(append(Budgets(nil), a.MaxDestroyed...), b.MaxDestroyed...),
+			MaxReplaced:  append(append(Budgets(nil), a.MaxReplaced...), b.MaxReplaced...),
+		}
+		err error
+	)
+
+	if result.CreatedResources, err = mergeCreateDeleteResourceChanges(a.CreatedResources, b.CreatedResources); err != nil {
+		return result, fmt.Errorf("createdResources: %v", err)
+	}
+	if result.DestroyedResources, err = mergeCreateDeleteResourceChanges(a.DestroyedResources, b.DestroyedResources); err != nil {
+		return result, fmt.Errorf("destroyedResources: %v", err)
+	}
+	if result.UpdatedResources, err = mergeUpdateResourceChanges(a.UpdatedResources, b.UpdatedResources); err != nil {
+		return result, fmt.Errorf("updatedResources: %v", err)
+	}
+	if result.ReplacedResources, err = mergeUpdateResourceChanges(a.ReplacedResources, b.ReplacedResources); err != nil {
+		return result, fmt.Errorf("replacedResources: %v", err)
+	}
+	if result.ReadResources, err = mergeCreateDeleteResourceChanges(a.ReadResources, b.ReadResources); err != nil {
+		return result, fmt.Errorf("readResources: %v", err)
+	}
+	if result.MovedResources, err = mergeOperationChanges(a.MovedResources, b.MovedResources); err != nil {
+		return result, fmt.Errorf("movedResources: %v", err)
+	}
+	if result.ImportedResources, err = mergeOperationChanges(a.ImportedResources, b.ImportedResources); err != nil {
+		return result, fmt.Errorf("importedResources: %v", err)
+	}
+	if result.ForgottenResources, err = mergeOperationChanges(a.ForgottenResources, b.ForgottenResources); err != nil {
+		return result, fmt.Errorf("forgottenResources: %v", err)
+	}
+	if result.DriftedResources, err = mergeUpdateResourceChanges(a.DriftedResources, b.DriftedResources); err != nil {
+		return result, fmt.Errorf("driftedResources: %v", err)
+	}
+
+	return result, nil
+}
+
+func mergeCreateDeleteResourceChanges(a, b *CreateDeleteResourceChanges) (*CreateDeleteResourceChanges, error) {
+	if a == nil {
+		return b, nil
+	}
+	if b == nil {
+		return a, nil
+	}
+
+	matchMode, err := mergeString("matchMode", a.MatchMode, b.MatchMode)
+	if err != nil {
+		return nil, err
+	}
+	defaultOpts, err := mergeDefaultCompareOptions(a.Default, b.Default)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := mergeResources(a.Resources, b.Resources)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateDeleteResourceChanges{
+		Strict:      a.Strict || b.Strict,
+		MatchMode:   matchMode,
+		RequireName: a.RequireName || b.RequireName,
+		Default:     defaultOpts,
+		Resources:   resources,
+	}, nil
+}
+
+func mergeUpdateResourceChanges(a, b *UpdateResourceChanges) (*UpdateResourceChanges, error) {
+	if a == nil {
+		return b, nil
+	}
+	if b == nil {
+		return a, nil
+	}
+
+	matchMode, err := mergeString("matchMode", a.MatchMode, b.MatchMode)
+	if err != nil {
+		return nil, err
+	}
+	defaultOpts, err := mergeDefaultCompareOptions(a.Default, b.Default)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := mergeResources(a.Resources, b.Resources)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdateResourceChanges{
+		Strict:      a.Strict || b.Strict,
+		MatchMode:   matchMode,
+		RequireName: a.RequireName || b.RequireName,
+		Default:     defaultOpts,
+		Resources:   resources,
+	}, nil
+}
+
+func mergeOperationChanges(a, b *OperationChanges) (*OperationChanges, error) {
+	if a == nil {
+		return b, nil
+	}
+	if b == nil {
+		return a, nil
+	}
+
+	resources, err := mergeResources(a.Resources, b.Resources)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OperationChanges{
+		Strict:    a.Strict || b.Strict,
+		Resources: resources,
+	}, nil
+}
+
+// mergeResources appends the resources, failing if both contain a rule for the same resource
+func mergeResources[T Resource](a, b []T) ([]T, error) {
+	ids := make(map[string]bool)
+	for _, r := range a {
+		ids[r.ID().String()] = true
+	}
+
+	for _, r := range b {
+		if id := r.ID().String(); ids[id] {
+			return nil, fmt.Errorf("duplicate rule for %s", id)
+		}
+	}
+
+	return append(append([]T{}, a...), b...), nil
+}
+
+func mergeDefaultCompareOptions(a, b *CompareOptions) (*CompareOptions, error) {
+	if a == nil {
+		return b, nil
+	}
+	if b == nil {
+		return a, nil
+	}
+
+	var (
+		result CompareOptions
+		err    error
+	)
+	if result.EnforceAll, err = mergeBool("enforceAll", a.EnforceAll, b.EnforceAll); err != nil {
+		return nil, err
+	}
+	if result.IgnoreExtraArgs, err = mergeBool("ignoreExtraArgs", a.IgnoreExtraArgs, b.IgnoreExtraArgs); err != nil {
+		return nil, err
+	}
+	if result.IgnoreComputed, err = mergeBool("ignoreComputed", a.IgnoreComputed, b.IgnoreComputed); err != nil {
+		return nil, err
+	}
+	if result.RequireAll, err = mergeBool("requireAll", a.RequireAll, b.RequireAll); err != nil {
+		return nil, err
+	}
+	if result.AutoFail, err = mergeBool("autoFail", a.AutoFail, b.AutoFail); err != nil {
+		return nil, err
+	}
+	if result.IgnoreNoOp, err = mergeBool("ignoreNoOp", a.IgnoreNoOp, b.IgnoreNoOp); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// mergeBool returns whichever option is set, failing if both are set to different values
+func mergeBool(name string, a, b *bool) (*bool, error) {
+	if a == nil {
+		return b, nil
+	}
+	if b != nil && *a != *b {
+		return nil, fmt.Errorf("conflicting values for default %s: %v and %v", name, *a, *b)
+	}
+	return a, nil
+}
+
+// mergeString returns whichever option is set, failing if both are set to different values
+func mergeString(name, a, b string) (string, error) {
+	if a == "" {
+		return b, nil
+	}
+	if b != "" && a != b {
+		return "", fmt.Errorf("conflicting values for %s: %s and %s", name, a, b)
+	}
+	return a, nil
+}
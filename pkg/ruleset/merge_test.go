@@ -0,0 +1,221 @@
+package ruleset
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func boolPointer(b bool) *bool {
+	return &b
+}
+
+func TestMerge(t *testing.T) {
+	cases := map[string]struct {
+		a           Ruleset
+		b           Ruleset
+		expected    Ruleset
+		expectedErr bool
+	}{
+		"empty": {
+			a:        Ruleset{},
+			b:        Ruleset{},
+			expected: Ruleset{},
+		},
+		"section in one ruleset": {
+			a: Ruleset{
+				CreatedResources: &CreateDeleteResourceChanges{
+					Strict: true,
+				},
+			},
+			b: Ruleset{
+				UpdatedResources: &UpdateResourceChanges{
+					Strict: true,
+				},
+			},
+			expected: Ruleset{
+				CreatedResources: &CreateDeleteResourceChanges{
+					Strict: true,
+				},
+				UpdatedResources: &UpdateResourceChanges{
+					Strict: true,
+				},
+			},
+		},
+		"merges section": {
+			a: Ruleset{
+				CreatedResources: &CreateDeleteResourceChanges{
+					Strict: true,
+					Default: &CompareOptions{
+						EnforceAll: boolPointer(true),
+					},
+					Resources: []CreateDeleteResourceChange{
+						{ResourceIdentifier: ResourceIdentifier{Type: "type"}},
+					},
+				},
+			},
+			b: Ruleset{
+				CreatedResources: &CreateDeleteResourceChanges{
+					MatchMode:   MatchModeAll,
+					RequireName: true,
+					Default: &CompareOptions{
+						EnforceAll:      boolPointer(true),
+						IgnoreExtraArgs: boolPointer(false),
+					},
+					Resources: []CreateDeleteResourceChange{
+						{ResourceIdentifier: ResourceIdentifier{Type: "type", Name: "name"}},
+					},
+				},
+			},
+			expected: Ruleset{
+				CreatedResources: &CreateDeleteResourceChanges{
+					Strict:      true,
+					MatchMode:   MatchModeAll,
+					RequireName: true,
+					Default: &CompareOptions{
+						EnforceAll:      boolPointer(true),
+						IgnoreExtraArgs: boolPointer(false),
+					},
+					Resources: []CreateDeleteResourceChange{
+						{ResourceIdentifier: ResourceIdentifier{Type: "type"}},
+						{ResourceIdentifier: ResourceIdentifier{Type: "type", Name: "name"}},
+					},
+				},
+			},
+		},
+		"conflicting default": {
+			a: Ruleset{
+				DestroyedResources: &CreateDeleteResourceChanges{
+					Default: &CompareOptions{
+						IgnoreComputed: boolPointer(true),
+					},
+				},
+			},
+			b: Ruleset{
+				DestroyedResources: &CreateDeleteResourceChanges{
+					Default: &CompareOptions{
+						IgnoreComputed: boolPointer(false),
+					},
+				},
+			},
+			expectedErr: true,
+		},
+		"conflicting matchMode": {
+			a: Ruleset{
+				UpdatedResources: &UpdateResourceChanges{
+					MatchMode: MatchModeAll,
+				},
+			},
+			b: Ruleset{
+				UpdatedResources: &UpdateResourceChanges{
+					MatchMode: MatchModeFirst,
+				},
+			},
+			expectedErr: true,
+		},
+		"duplicate resource": {
+			a: Ruleset{
+				UpdatedResources: &UpdateResourceChanges{
+					Resources: []UpdateResourceChange{
+						{ResourceIdentifier: ResourceIdentifier{Type: "type", Index: 1}},
+					},
+				},
+			},
+			b: Ruleset{
+				UpdatedResources: &UpdateResourceChanges{
+					Resources: []UpdateResourceChange{
+						{ResourceIdentifier: ResourceIdentifier{Type: "type", Index: 1}},
+					},
+				},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Merge(tc.a, tc.b)
+			if tc.expectedErr {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(got, tc.expected); diff != "" {
+				t.Errorf("(-got, +expected)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseRulesetInclude(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"base/created.yaml": `
+createdResources:
+  strict: true
+  resources:
+    - type: aws_s3_bucket
+`,
+		"base/destroyed.yaml": `
+include:
+  - created.yaml
+destroyedResources:
+  resources:
+    - type: aws_db_instance
+`,
+		"service.yaml": `
+include:
+  - base/*.yaml
+createdResources:
+  resources:
+    - type: aws_s3_bucket
+      name: logs
+`,
+		"other.yaml": `
+updatedResources:
+  resources:
+    - type: aws_instance
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rs, err := ParseRuleset(filepath.Join(dir, "service.yaml"), filepath.Join(dir, "other.yaml"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var created []string
+	for _, r := range rs.CreatedResources.Resources {
+		created = append(created, r.ID().String())
+	}
+	if diff := cmp.Diff(created, []string{"aws_s3_bucket", "aws_s3_bucket.logs"}); diff != "" {
+		t.Errorf("(-got, +expected)\n%s", diff)
+	}
+	if !rs.CreatedResources.Strict {
+		t.Errorf("Expected strict to be merged from included ruleset")
+	}
+	if rs.DestroyedResources == nil || len(rs.DestroyedResources.Resources) != 1 {
+		t.Errorf("Expected destroyed resources from included ruleset but got %v", rs.DestroyedResources)
+	}
+	if rs.UpdatedResources == nil || len(rs.UpdatedResources.Resources) != 1 {
+		t.Errorf("Expected updated resources from second ruleset but got %v", rs.UpdatedResources)
+	}
+
+	if _, err := ParseRuleset(filepath.Join(dir, "missing", "*.yaml")); err == nil {
+		t.Errorf("Expected error for missing ruleset")
+	}
+}
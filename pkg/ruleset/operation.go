@@ -0,0 +1,32 @@
+package ruleset
+
+// OperationChanges are rules for resources with a state operation, such as moved, imported or forgotten resources
+// These rules are checked in addition to the rules for the planned action of the resource
+type OperationChanges struct {
+	// If strict is enabled, all resources with the operation must match a rule
+	Strict bool `yaml:"strict,omitempty"`
+
+	// Resources is a list of rules, checked in order
+	// The first matching rule is applied
+	Resources []OperationChange `yaml:"resources"`
+}
+
+type OperationChange struct {
+	ResourceIdentifier `yaml:",inline"`
+
+	// Deny fails the operation for matching resources
+	Deny bool `yaml:"deny,omitempty"`
+
+	// From is a list of address globs the resource can be moved from
+	// Only used by moved resources, and any previous address is allowed if empty
+	From []string `yaml:"from,omitempty"`
+}
+
+func (r OperationChange) ID() *ResourceIdentifier {
+	return &r.ResourceIdentifier
+}
+
+// Rules returns no rules, as operation rules do not compare values
+func (r OperationChange) Rules() []ResourceRules {
+	return nil
+}
@@ -0,0 +1,54 @@
+package ruleset
+
+import "fmt"
+
+const (
+	ActionCreate  = "create"
+	ActionDestroy = "destroy"
+	ActionUpdate  = "update"
+	ActionReplace = "replace"
+	ActionRead    = "read"
+)
+
+// PlanRule is a rule on the relationship between resources in the plan
+// Every resource matching Resource must have a resource matching Requires in the same plan
+type PlanRule struct {
+	// Name describes the rule in diff output
+	// Defaults to a description of the resources
+	Name string `yaml:"name,omitempty"`
+
+	// Resource selects the resources the rule applies to
+	Resource PlanRuleSelector `yaml:"resource"`
+
+	// Requires selects the resources that must exist for each selected resource
+	Requires PlanRuleSelector `yaml:"requires"`
+
+	// Match maps arguments of the required resource to arguments of the selected resource they must be equal to
+	// Arguments are referenced by their full dotted path
+	Match map[string]string `yaml:"match,omitempty"`
+}
+
+// String returns the name of the rule, or a description of the resources if it has no name
+func (r PlanRule) String() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("%s requires %s", r.Resource.String(), r.Requires.String())
+}
+
+// PlanRuleSelector selects resources in the plan by their identifier and planned action
+type PlanRuleSelector struct {
+	ResourceIdentifier `yaml:",inline"`
+
+	// Actions is a list of "create", "destroy", "update", "replace" and "read"
+	// If empty, every resource in the plan is selected, including resources without changes in JSON plans
+	Actions []string `yaml:"actions,omitempty"`
+}
+
+// String returns the resource identifier, followed by the actions if set
+func (s PlanRuleSelector) String() string {
+	if len(s.Actions) == 0 {
+		return s.ResourceIdentifier.String()
+	}
+	return fmt.Sprintf("%s %v", s.ResourceIdentifier.String(), s.Actions)
+}
@@ -3,27 +3,68 @@ package ruleset
 import (
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
 
 	yaml "gopkg.in/yaml.v2"
 )
 
 type Resource interface {
 	ID() *ResourceIdentifier
+	Rules() []ResourceRules
 }
 
 type Ruleset struct {
+	// Include is a list of other ruleset files to merge into this ruleset
+	// Paths are relative to this ruleset file and can be globs
+	Include []string `yaml:"include,omitempty"`
+
 	CreatedResources   *CreateDeleteResourceChanges `yaml:"createdResources,omitempty"`
 	DestroyedResources *CreateDeleteResourceChanges `yaml:"destroyedResources,omitempty"`
 	UpdatedResources   *UpdateResourceChanges       `yaml:"updatedResources,omitempty"`
+
+	// ReplacedResources are rules for resources that are destroyed and created again
+	// If not set, replaced resources are compared against UpdatedResources
+	ReplacedResources *UpdateResourceChanges `yaml:"replacedResources,omitempty"`
+
+	// ReadResources are rules for data sources read during apply, as their configuration depends on values not yet known
+	// The values after the read are compared, like created resources
+	ReadResources *CreateDeleteResourceChanges `yaml:"readResources,omitempty"`
+
+	// MovedResources, ImportedResources and ForgottenResources are rules for resources that are moved to a new address,
+	// imported, or removed from the state without being destroyed
+	// They are only supported in JSON plans
+	MovedResources     *OperationChanges `yaml:"movedResources,omitempty"`
+	ImportedResources  *OperationChanges `yaml:"importedResources,omitempty"`
+	ForgottenResources *OperationChanges `yaml:"forgottenResources,omitempty"`
+
+	// DriftedResources are rules for resources changed outside of Terraform, from the resource drift of the plan
+	// Before rules are compared against the values in the state, and after rules against the values changed outside of Terraform
+	// They are only supported in JSON plans
+	DriftedResources *UpdateResourceChanges `yaml:"driftedResources,omitempty"`
+
+	// PlanRules are rules on the relationships between resources in the plan
+	PlanRules []PlanRule `yaml:"planRules,omitempty"`
+
+	// MaxCreated, MaxDestroyed and MaxReplaced limit the number of resources with each action in the plan
+	// Replaced resources are also counted as created and destroyed
+	MaxCreated   Budgets `yaml:"maxCreated,omitempty"`
+	MaxDestroyed Budgets `yaml:"maxDestroyed,omitempty"`
+	MaxReplaced  Budgets `yaml:"maxReplaced,omitempty"`
 }
 
 type CreateDeleteResourceChanges struct {
 	// If strict is enabled, all created or deleted resources must match a rule
 	Strict bool `yaml:"strict,omitempty"`
 
+	// MatchMode is either "first" or "all"
+	// If "all", every matching rule is applied instead of only the highest priority one
+	MatchMode string `yaml:"matchMode,omitempty"`
+
 	// If requireName is enabled, all resources must specify the name of the
 	// resource in addition to the resource type
-	RequireName bool `yaml:"requireName",omitempty`
+	RequireName bool `yaml:"requireName,omitempty"`
 
 	// Default CompareOptions to use for all resources
 	Default *CompareOptions `yaml:"default,omitempty"`
@@ -36,6 +77,10 @@ func (r CreateDeleteResourceChange) ID() *ResourceIdentifier {
 	return &r.ResourceIdentifier
 }
 
+func (r CreateDeleteResourceChange) Rules() []ResourceRules {
+	return []ResourceRules{r.ResourceRules}
+}
+
 type CreateDeleteResourceChange struct {
 	CompareOptions     `yaml:",inline"`
 	ResourceIdentifier `yaml:",inline"`
@@ -46,9 +91,13 @@ type UpdateResourceChanges struct {
 	// If strict is enabled, all updated resources must match a rule
 	Strict bool `yaml:"strict,omitempty"`
 
+	// MatchMode is either "first" or "all"
+	// If "all", every matching rule is applied instead of only the highest priority one
+	MatchMode string `yaml:"matchMode,omitempty"`
+
 	// If requireName is enabled, all resources must specify the name of the
 	// resource in addition to the resource type
-	RequireName bool `yaml:"requireName",omitempty`
+	RequireName bool `yaml:"requireName,omitempty"`
 
 	// Default CompareOptions to use for all resources
 	Default *CompareOptions `yaml:"default,omitempty"`
@@ -63,12 +112,28 @@ type UpdateResourceChange struct {
 
 	Before *ResourceRules `yaml:"before,omitempty"`
 	After  *ResourceRules `yaml:"after,omitempty"`
+
+	// AllowedReplaceTriggers is a list of the arguments allowed to force a replacement of the resource
+	// If set, replacements forced by any other argument, or without a known argument, fail, so an empty list forbids replacements
+	// If set, replacements forced by any other argument, or without a known argument, fail
+	AllowedReplaceTriggers []string `yaml:"allowedReplaceTriggers,omitempty"`
 }
 
 func (r UpdateResourceChange) ID() *ResourceIdentifier {
 	return &r.ResourceIdentifier
 }
 
+func (r UpdateResourceChange) Rules() []ResourceRules {
+	var rules []ResourceRules
+	if r.Before != nil {
+		rules = append(rules, *r.Before)
+	}
+	if r.After != nil {
+		rules = append(rules, *r.After)
+	}
+	return rules
+}
+
 type CompareOptions struct {
 	// If enforceAll is enabled, all Enforced must be present
 	EnforceAll *bool `yaml:"enforceAll,omitempty"`
@@ -91,18 +156,109 @@ type CompareOptions struct {
 	IgnoreNoOp *bool `yaml:"ignoreNoOp,omitempty"`
 }
 
+const (
+	// IndexWildcard matches any index of a resource created with "count" or "for_each"
+	IndexWildcard = "*"
+
+	// ModuleWildcardSuffix matches a module and every module nested under it
+	// Example: module.network.* matches module.network and module.network.module.subnets
+	ModuleWildcardSuffix = ".*"
+
+	// MatchModeFirst only applies the highest priority rule that matches a resource
+	MatchModeFirst = "first"
+
+	// MatchModeAll applies every rule that matches a resource
+	MatchModeAll = "all"
+)
+
 type ResourceIdentifier struct {
 	Name string `yaml:"name,omitempty"`
 	Type string `yaml:"type,omitempty"`
-	// TODO: index
-	// Index interface{} `yaml:"index,omitempty"`
+
+	// Module matches the module address of the resource
+	// Suffix with ".*" to also match every nested module
+	Module string `yaml:"module,omitempty"`
+
+	// Index matches the key of a resource created with "count" (int) or "for_each" (string)
+	// Set to "*" to match any indexed resource
+	Index interface{} `yaml:"index,omitempty"`
+
+	// Address matches the full resource address with a glob pattern
+	// "*" matches any characters except ".", and "**" matches any characters
+	// If set, Name, Type, Module and Index are ignored
+	Address string `yaml:"address,omitempty"`
+
+	// AddressRegex matches the full resource address with a regular expression
+	// If set, Name, Type, Module and Index are ignored
+	AddressRegex string `yaml:"addressRegex,omitempty"`
+}
+
+// IsAddressPattern returns true if the resource is matched on its address instead of its name and type
+func (id *ResourceIdentifier) IsAddressPattern() bool {
+	return id.Address != "" || id.AddressRegex != ""
+}
+
+// AddressPattern compiles Address or AddressRegex into a regular expression
+// matching the full resource address
+func (id *ResourceIdentifier) AddressPattern() (*regexp.Regexp, error) {
+	if id.AddressRegex != "" {
+		return regexp.Compile(id.AddressRegex)
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	for i := 0; i < len(id.Address); i++ {
+		switch {
+		case strings.HasPrefix(id.Address[i:], "**"):
+			pattern.WriteString(".*")
+			i++
+		case id.Address[i] == '*':
+			pattern.WriteString("[^.]*")
+		case id.Address[i] == '?':
+			pattern.WriteString("[^.]")
+		default:
+			pattern.WriteString(regexp.QuoteMeta(id.Address[i : i+1]))
+		}
+	}
+	pattern.WriteString("$")
+
+	return regexp.Compile(pattern.String())
 }
 
 func (id *ResourceIdentifier) String() string {
-	if id.Name == "" {
-		return id.Type
+	if id.AddressRegex != "" {
+		return fmt.Sprintf("/%s/", id.AddressRegex)
+	}
+	if id.Address != "" {
+		return id.Address
+	}
+
+	result := id.Type
+	if id.Name != "" {
+		result = fmt.Sprintf("%s.%s", id.Type, id.Name)
+	}
+	if id.Module != "" {
+		result = fmt.Sprintf("%s.%s", id.Module, result)
+	}
+	return result + FormatIndex(id.Index)
+}
+
+// FormatIndex formats the index the same way terraform displays it in a resource address
+// Example: 1 -> "[1]", "blue" -> "[\"blue\"]"
+func FormatIndex(index interface{}) string {
+	switch i := index.(type) {
+	case nil:
+		return ""
+	case string:
+		if i == IndexWildcard {
+			return "[*]"
+		}
+		return fmt.Sprintf("[%q]", i)
+	case float64:
+		return fmt.Sprintf("[%d]", int(i))
+	default:
+		return fmt.Sprintf("[%v]", i)
 	}
-	return fmt.Sprintf("%s.%s", id.Type, id.Name)
 }
 
 type ResourceRules struct {
@@ -111,18 +267,221 @@ type ResourceRules struct {
 }
 
 type EnforceChange struct {
-	Value         interface{}              `yaml:"value,omitempty"`
-	MatchAny      []interface{}            `yaml:"matchAny,omitempty"`
+	Value    interface{}   `yaml:"value,omitempty"`
+	MatchAny []interface{} `yaml:"matchAny,omitempty"`
+
+	// Unordered compares lists in value and matchAny without considering the order of their elements
+	Unordered bool `yaml:"unordered,omitempty"`
+
+	// ContainsAll requires the value to be a list containing every element of ContainsAll
+	ContainsAll []interface{} `yaml:"containsAll,omitempty"`
+
+	// ContainsAny requires the value to be a list containing at least one element of ContainsAny
+	ContainsAny []interface{} `yaml:"containsAny,omitempty"`
+
+	// SubsetOf requires the value to be a list whose elements are all in SubsetOf
+	SubsetOf []interface{} `yaml:"subsetOf,omitempty"`
+
+	// Exists requires the attribute to be present if true, or absent if false
+	Exists *bool `yaml:"exists,omitempty"`
+
+	// Null requires the attribute to be null or absent if true, or set to a non-null value if false
+	Null *bool `yaml:"null,omitempty"`
+
+	// MatchRegex requires the value to match the regular expression
+	MatchRegex string `yaml:"matchRegex,omitempty"`
+
+	// NotMatchRegex requires the value to not match the regular expression
+	NotMatchRegex string `yaml:"notMatchRegex,omitempty"`
+
+	// HasPrefix requires the value to be a string starting with HasPrefix
+	HasPrefix string `yaml:"hasPrefix,omitempty"`
+
+	// HasSuffix requires the value to be a string ending with HasSuffix
+	HasSuffix string `yaml:"hasSuffix,omitempty"`
+
+	// Contains requires the value to be a string containing Contains
+	Contains string `yaml:"contains,omitempty"`
+
+	// EqualFold requires the value to be a string equal to EqualFold, ignoring case
+	EqualFold string `yaml:"equalFold,omitempty"`
+
+	// WithinCIDR requires the value, or every element of a list, to be a CIDR inside one of the WithinCIDR ranges
+	WithinCIDR []string `yaml:"withinCIDR,omitempty"`
+
+	// NotOverlapCIDR requires the value, or every element of a list, to be a CIDR that does not overlap any of the NotOverlapCIDR ranges
+	NotOverlapCIDR []string `yaml:"notOverlapCIDR,omitempty"`
+
+	// VersionConstraint requires the value to be a version satisfying the constraint, such as ">= 1.27, < 1.30"
+	VersionConstraint string `yaml:"versionConstraint,omitempty"`
+
+	// Min requires the value to be a number greater than or equal to Min
+	Min *float64 `yaml:"min,omitempty"`
+
+	// Max requires the value to be a number less than or equal to Max
+	Max *float64 `yaml:"max,omitempty"`
+
+	// GreaterThan requires the value to be a number greater than GreaterThan
+	GreaterThan *float64 `yaml:"greaterThan,omitempty"`
+
+	// LessThan requires the value to be a number less than LessThan
+	LessThan *float64 `yaml:"lessThan,omitempty"`
+
+	// EqualsAttr requires the value to be equal to the value of another attribute of the resource, referenced by its path
+	// Attribute matchers such as EqualsAttr pass if either value is computed, as the value is unknown until apply
+	EqualsAttr string `yaml:"equalsAttr,omitempty"`
+
+	// NotEqualsAttr requires the value to not be equal to the value of another attribute of the resource
+	NotEqualsAttr string `yaml:"notEqualsAttr,omitempty"`
+
+	// MinAttr requires the value to be a number greater than or equal to the value of another attribute of the resource
+	MinAttr string `yaml:"minAttr,omitempty"`
+
+	// MaxAttr requires the value to be a number less than or equal to the value of another attribute of the resource
+	MaxAttr string `yaml:"maxAttr,omitempty"`
+
+	// GreaterThanAttr requires the value to be a number greater than the value of another attribute of the resource
+	GreaterThanAttr string `yaml:"greaterThanAttr,omitempty"`
+
+	// LessThanAttr requires the value to be a number less than the value of another attribute of the resource
+	LessThanAttr string `yaml:"lessThanAttr,omitempty"`
+
+	// IncreaseOnly requires the value of an updated resource to be a number greater than or equal to its value before the change
+	// Relational matchers such as IncreaseOnly are only supported in the after rules of updated resources
+	IncreaseOnly bool `yaml:"increaseOnly,omitempty"`
+
+	// DecreaseOnly requires the value of an updated resource to be a number less than or equal to its value before the change
+	DecreaseOnly bool `yaml:"decreaseOnly,omitempty"`
+
+	// Unchanged requires the value of an updated resource to be equal to its value before the change
+	Unchanged bool `yaml:"unchanged,omitempty"`
+
+	// MaxDelta requires the value of an updated resource to be a number that changed by at most MaxDelta
+	MaxDelta *float64 `yaml:"maxDelta,omitempty"`
+
+	// MaxPercentChange requires the value of an updated resource to be a number that changed by at most MaxPercentChange percent
+	MaxPercentChange *float64 `yaml:"maxPercentChange,omitempty"`
+
+	// Not requires the value to not match the nested matchers
+	Not *EnforceChange `yaml:"not,omitempty"`
+
+	// AllOf requires the value to match every nested matcher
+	AllOf []EnforceChange `yaml:"allOf,omitempty"`
+
+	// AnyOf requires the value to match at least one nested matcher
+	AnyOf []EnforceChange `yaml:"anyOf,omitempty"`
+
+	// OneOf requires the value to match exactly one nested matcher
+	OneOf []EnforceChange `yaml:"oneOf,omitempty"`
+
+	// JSON decodes a JSON string and requires the decoded document to match the nested rules
+	JSON *EnforceChange `yaml:"json,omitempty"`
+
+	// Each requires every element of a list to match the nested rules
+	Each *EnforceChange `yaml:"each,omitempty"`
+
+	// Any requires at least one element of a list to match the nested rules
+	Any *EnforceChange `yaml:"any,omitempty"`
+
+	// None requires no element of a list to match the nested rules
+	None *EnforceChange `yaml:"none,omitempty"`
+
+	// Attributes are rules for nested attributes, like the inline keys
+	// They are needed for nested attributes named like a matcher, such as min, and are merged into EnforceChange when parsed
+	Attributes map[string]EnforceChange `yaml:"attributes,omitempty"`
+
 	EnforceChange map[string]EnforceChange `yaml:",inline"`
 }
 
-func ParseRuleset(path string) (Ruleset, error) {
+func (e *EnforceChange) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain EnforceChange
+	if err := unmarshal((*plain)(e)); err != nil {
+		return err
+	}
+
+	for k, v := range e.Attributes {
+		if _, ok := e.EnforceChange[k]; ok {
+			return fmt.Errorf("nested attribute %s is set both inline and in attributes", k)
+		}
+		if e.EnforceChange == nil {
+			e.EnforceChange = make(map[string]EnforceChange)
+		}
+		e.EnforceChange[k] = v
+	}
+	e.Attributes = nil
+	return nil
+}
+
+// ParseRuleset parses and merges the rulesets at the given paths, along with every included ruleset
+// Included rulesets are merged before the ruleset including them, and each file is only parsed once
+func ParseRuleset(paths ...string) (Ruleset, error) {
 	var rs Ruleset
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		parsed, err := parseRulesetFile(path, seen)
+		if err != nil {
+			return rs, err
+		}
+
+		if rs, err = Merge(rs, parsed); err != nil {
+			return rs, err
+		}
+	}
+
+	return rs, nil
+}
+
+func parseRulesetFile(path string, seen map[string]bool) (Ruleset, error) {
+	var rs Ruleset
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return rs, err
+	}
+	if seen[abs] {
+		return rs, nil
+	}
+	seen[abs] = true
+
 	rulesetFile, err := ioutil.ReadFile(path)
 	if err != nil {
 		return rs, err
 	}
 
-	err = yaml.Unmarshal(rulesetFile, &rs)
-	return rs, err
+	if err = yaml.Unmarshal(rulesetFile, &rs); err != nil {
+		return rs, err
+	}
+
+	var result Ruleset
+	for _, include := range rs.Include {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+
+		matches, err := filepath.Glob(include)
+		if err != nil {
+			return rs, err
+		}
+		if len(matches) == 0 {
+			return rs, fmt.Errorf("include %s in %s matched no files", include, path)
+		}
+
+		for _, match := range matches {
+			included, err := parseRulesetFile(match, seen)
+			if err != nil {
+				return rs, err
+			}
+
+			if result, err = Merge(result, included); err != nil {
+				return rs, fmt.Errorf("failed to merge %s: %v", match, err)
+			}
+		}
+	}
+
+	rs.Include = nil
+	result, err = Merge(result, rs)
+	if err != nil {
+		return rs, fmt.Errorf("failed to merge %s: %v", path, err)
+	}
+
+	return result, nil
 }
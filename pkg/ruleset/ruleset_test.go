@@ -0,0 +1,83 @@
+package ruleset
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestEnforceChangeUnmarshalYAML(t *testing.T) {
+	cases := map[string]struct {
+		input       string
+		expected    map[string]EnforceChange
+		expectedErr bool
+	}{
+		"nested attributes named like matchers": {
+			input: `
+vcpu_count:
+  attributes:
+    min:
+      value: 2
+    max:
+      value: 8
+`,
+			expected: map[string]EnforceChange{
+				"vcpu_count": {
+					EnforceChange: map[string]EnforceChange{
+						"min": {Value: 2},
+						"max": {Value: 8},
+					},
+				},
+			},
+		},
+		"attributes merged with inline keys": {
+			input: `
+instance_requirements:
+  burstable_performance:
+    value: excluded
+  attributes:
+    min:
+      value: 2
+`,
+			expected: map[string]EnforceChange{
+				"instance_requirements": {
+					EnforceChange: map[string]EnforceChange{
+						"burstable_performance": {Value: "excluded"},
+						"min":                   {Value: 2},
+					},
+				},
+			},
+		},
+		"attribute set both inline and in attributes": {
+			input: `
+tags:
+  Env:
+    value: prod
+  attributes:
+    Env:
+      value: dev
+`,
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got map[string]EnforceChange
+			err := yaml.Unmarshal([]byte(tc.input), &got)
+			if tc.expectedErr {
+				if err == nil {
+					t.Errorf("Expected error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(got, tc.expected); diff != "" {
+				t.Errorf("(-got, +expected)\n%s", diff)
+			}
+		})
+	}
+}